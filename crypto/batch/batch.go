@@ -0,0 +1,29 @@
+package batch
+
+import (
+	"github.com/dashpay/tenderdash/crypto"
+	"github.com/dashpay/tenderdash/crypto/bls12381"
+)
+
+// CreateBatchVerifier checks if a key type implements the batch verifier interface.
+// Currently only bls12381 supports batch verification.
+func CreateBatchVerifier(pk crypto.PubKey) (crypto.BatchVerifier, bool) {
+	switch pk.Type() {
+	case bls12381.KeyType:
+		return bls12381.NewBatchVerifier(), true
+	}
+
+	// case where the key does not support batch verification
+	return nil, false
+}
+
+// SupportsBatchVerifier checks if a key type implements the batch verifier
+// interface.
+func SupportsBatchVerifier(pk crypto.PubKey) bool {
+	switch pk.Type() {
+	case bls12381.KeyType:
+		return true
+	}
+
+	return false
+}
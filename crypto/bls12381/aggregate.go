@@ -0,0 +1,97 @@
+package bls12381
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+var (
+	errNothingToAggregate = errors.New("nothing to aggregate")
+)
+
+// AggregateSignatures combines signatures into a single signature.
+// The result can be checked with VerifyAggregateSameMessage when all signatures
+// sign the same message, or with VerifyAggregateDistinctMessages otherwise.
+func AggregateSignatures(sigs [][]byte) ([]byte, error) {
+	if len(sigs) == 0 {
+		return nil, errNothingToAggregate
+	}
+	for i, sig := range sigs {
+		if len(sig) != SignatureSize {
+			return nil, fmt.Errorf("signature %d has wrong size %d, expected %d", i, len(sig), SignatureSize)
+		}
+	}
+	return defaultBackend.aggregateSignatures(sigs)
+}
+
+// AggregatePubKeys combines public keys into a single public key.
+// A signature aggregated from signatures of one message verifies against the
+// aggregated public key of the signers. Public keys at infinity are rejected.
+func AggregatePubKeys(pubKeys []crypto.PubKey) (PubKey, error) {
+	if len(pubKeys) == 0 {
+		return nil, errNothingToAggregate
+	}
+	pks, err := pubKeysBytes(pubKeys)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNotInfinity(pks); err != nil {
+		return nil, err
+	}
+	aggregate, err := defaultBackend.aggregatePubKeys(pks)
+	if err != nil {
+		return nil, err
+	}
+	return PubKey(aggregate), nil
+}
+
+// VerifyAggregateSameMessage reports whether sig is an aggregate of signatures
+// of msg by every one of pubKeys (fast aggregate verify).
+//
+// The basic scheme does not protect against rogue public keys, so the caller
+// must know that every public key is backed by its private key. Use the PoP
+// scheme and PopFastAggregateVerify to have signers prove it.
+func VerifyAggregateSameMessage(pubKeys []crypto.PubKey, msg []byte, sig []byte) bool {
+	if len(pubKeys) == 0 {
+		return false
+	}
+	aggregate, err := AggregatePubKeys(pubKeys)
+	if err != nil {
+		return false
+	}
+	return SchemeBasic.verifyOnce(aggregate, msg, sig)
+}
+
+// VerifyAggregateDistinctMessages reports whether sig is an aggregate of
+// signatures of msgs[i] by pubKeys[i].
+//
+// As required by the basic scheme, messages must be pairwise distinct; the
+// verification fails otherwise.
+func VerifyAggregateDistinctMessages(pubKeys []crypto.PubKey, msgs [][]byte, sig []byte) bool {
+	return SchemeBasic.AggregateVerify(pubKeys, msgs, sig)
+}
+
+// pubKeysBytes returns the raw bytes of BLS12-381 public keys.
+func pubKeysBytes(pubKeys []crypto.PubKey) ([][]byte, error) {
+	pks := make([][]byte, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		if pubKey.Type() != KeyType {
+			return nil, fmt.Errorf("public key %d has type %s, expected %s", i, pubKey.Type(), KeyType)
+		}
+		pks[i] = pubKey.Bytes()
+	}
+	return pks, nil
+}
+
+// checkNotInfinity returns an error for the first public key at infinity, as
+// such keys would let anyone add signatures to aggregates.
+func checkNotInfinity(pks [][]byte) error {
+	for i, pk := range pks {
+		if isInfinity(pk) {
+			return fmt.Errorf("public key %d: %w", i, ErrPubKeyInfinity)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// nolint:lll
+package bls12381
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+func TestAggregateSignaturesVectors(t *testing.T) {
+	// vectors of the dashpay/bls-signatures basic scheme
+	sk1 := genPrivKey(bytes.NewReader(bytes.Repeat([]byte{0}, SeedSize)))
+	sk2 := genPrivKey(bytes.NewReader(bytes.Repeat([]byte{1}, SeedSize)))
+	pk1, pk2 := sk1.PubKey(), sk2.PubKey()
+	msgs := [][]byte{{7, 8, 9}, {10, 11, 12}, {1, 2, 3}, {1, 2, 3, 4}, {1, 2}}
+
+	sig1 := mustSign(t, sk1, msgs[0])
+	sig2 := mustSign(t, sk2, msgs[1])
+	aggSig1, err := AggregateSignatures([][]byte{sig1, sig2})
+	require.NoError(t, err)
+	assert.Equal(t, "aee003c8cdaf3531b6b0ca354031b0819f7586b5846796615aee8108fec75ef838d181f9d244a94d195d7b0231d4afcf06f27f0cc4d3c72162545c240de7d5034a7ef3a2a03c0159de982fbc2e7790aeb455e27beae91d64e077c70b5506dea3", hex.EncodeToString(aggSig1))
+	assert.True(t, VerifyAggregateDistinctMessages([]crypto.PubKey{pk1, pk2}, msgs[:2], aggSig1))
+	assert.False(t, VerifyAggregateDistinctMessages([]crypto.PubKey{pk1, pk2}, msgs[:2], sig1))
+	assert.False(t, VerifyAggregateDistinctMessages([]crypto.PubKey{pk2, pk1}, msgs[:2], aggSig1))
+
+	sig3 := mustSign(t, sk1, msgs[2])
+	sig4 := mustSign(t, sk1, msgs[3])
+	sig5 := mustSign(t, sk2, msgs[4])
+	aggSig2, err := AggregateSignatures([][]byte{sig3, sig4, sig5})
+	require.NoError(t, err)
+	assert.Equal(t, "a0b1378d518bea4d1100adbc7bdbc4ff64f2c219ed6395cd36fe5d2aa44a4b8e710b607afd965e505a5ac3283291b75413d09478ab4b5cfbafbeea366de2d0c0bcf61deddaa521f6020460fd547ab37659ae207968b545727beba0a3c5572b9c", hex.EncodeToString(aggSig2))
+	assert.True(t, VerifyAggregateDistinctMessages([]crypto.PubKey{pk1, pk1, pk2}, msgs[2:], aggSig2))
+	assert.False(t, VerifyAggregateDistinctMessages([]crypto.PubKey{pk1, pk1, pk2}, msgs[2:], aggSig1))
+}
+
+func TestVerifyAggregateSameMessage(t *testing.T) {
+	msg := []byte{100, 2, 254, 88, 90, 45, 23}
+	privKeys := []PrivKey{GenPrivKey(), GenPrivKey(), GenPrivKey()}
+	pubKeys := make([]crypto.PubKey, len(privKeys))
+	sigs := make([][]byte, len(privKeys))
+	for i, privKey := range privKeys {
+		pubKeys[i] = privKey.PubKey()
+		sigs[i] = mustSign(t, privKey, msg)
+	}
+	aggSig, err := AggregateSignatures(sigs)
+	require.NoError(t, err)
+
+	assert.True(t, VerifyAggregateSameMessage(pubKeys, msg, aggSig))
+	assert.False(t, VerifyAggregateSameMessage(pubKeys[:2], msg, aggSig))
+	assert.False(t, VerifyAggregateSameMessage(pubKeys, []byte("other message"), aggSig))
+	assert.False(t, VerifyAggregateSameMessage(nil, msg, aggSig))
+
+	aggPubKey, err := AggregatePubKeys(pubKeys)
+	require.NoError(t, err)
+	assert.True(t, aggPubKey.VerifySignature(msg, aggSig))
+
+	// the same message signed twice is not accepted by the basic scheme
+	assert.False(t, VerifyAggregateDistinctMessages(pubKeys, [][]byte{msg, msg, msg}, aggSig))
+}
+
+func TestAggregateInvalidInput(t *testing.T) {
+	_, err := AggregateSignatures(nil)
+	assert.Error(t, err)
+	_, err = AggregateSignatures([][]byte{make([]byte, SignatureSize-1)})
+	assert.Error(t, err)
+	_, err = AggregatePubKeys(nil)
+	assert.Error(t, err)
+	_, err = AggregatePubKeys([]crypto.PubKey{PubKey(make([]byte, PubKeySize))})
+	assert.Error(t, err)
+}
+
+func TestAggregatePubKeysInfinity(t *testing.T) {
+	msg := []byte("msg")
+	privKey := GenPrivKey()
+	sig := mustSign(t, privKey, msg)
+	pubKeys := []crypto.PubKey{privKey.PubKey(), infinityPubKey()}
+
+	_, err := AggregatePubKeys(pubKeys)
+	assert.ErrorIs(t, err, ErrPubKeyInfinity)
+	// without the check, the aggregate would be the key of privKey alone
+	assert.False(t, VerifyAggregateSameMessage(pubKeys, msg, sig))
+}
+
+// infinityPubKey returns the encoding of the public key at infinity.
+func infinityPubKey() PubKey {
+	return PubKey(append([]byte{0xc0}, make([]byte, PubKeySize-1)...))
+}
+
+func mustSign(t *testing.T, privKey PrivKey, msg []byte) []byte {
+	t.Helper()
+	sig, err := privKey.Sign(msg)
+	require.NoError(t, err)
+	return sig
+}
@@ -0,0 +1,86 @@
+package bls12381
+
+// backend implements the BLS12-381 primitives the rest of this package is
+// built on. Keys, signatures and ids cross the interface in their serialized
+// form, so that implementations are interchangeable and can be checked against
+// each other byte for byte.
+//
+// Two implementations exist: cgoBackend, which wraps the dashpay/bls-signatures
+// C++ library, and goBackend, which is written in pure Go. The one used by the
+// package is selected at build time, see defaultBackend.
+type backend interface {
+	// keyGen derives a private key from a seed of at least SeedSize bytes.
+	keyGen(seed []byte) ([]byte, error)
+	// publicKey returns the compressed G1 public key of the private key sk.
+	// Private keys larger than the group order wrap around.
+	publicKey(sk []byte) ([]byte, error)
+	// sign signs msg with the private key sk using the given scheme.
+	// Private keys larger than the group order wrap around.
+	sign(scheme Scheme, sk, msg []byte) ([]byte, error)
+	// prepareSecretKey decodes the private key sk for repeated use. The
+	// returned key may refer to sk, which must be kept intact until the key is
+	// destroyed.
+	prepareSecretKey(sk []byte) (preparedSecretKey, error)
+	// verify reports whether sig is a valid signature of msg by pk under the
+	// given scheme.
+	verify(scheme Scheme, pk, msg, sig []byte) bool
+	// preparePubKey decodes pk once, so that signatures by pk can be verified
+	// repeatedly without decoding it again.
+	preparePubKey(pk []byte) (preparedPubKey, error)
+	// validatePubKey checks that pk decodes to a point of the G1 subgroup.
+	validatePubKey(pk []byte) error
+	// validateSignature checks that sig decodes to a point of the G2 subgroup.
+	validateSignature(sig []byte) error
+	// aggregatePubKeys adds public keys together.
+	aggregatePubKeys(pks [][]byte) ([]byte, error)
+	// aggregateSignatures adds signatures together.
+	aggregateSignatures(sigs [][]byte) ([]byte, error)
+	// mulPubKey multiplies a public key by a big-endian scalar.
+	mulPubKey(pk, scalar []byte) ([]byte, error)
+	// mulSignature multiplies a signature by a big-endian scalar.
+	mulSignature(sig, scalar []byte) ([]byte, error)
+	// aggregateVerify reports whether sig is the aggregate of signatures of
+	// msgs[i] by pks[i] under the given scheme. Callers check that messages are
+	// distinct where the scheme requires it.
+	aggregateVerify(scheme Scheme, pks, msgs [][]byte, sig []byte) bool
+	// prepareBatchEntry decodes the public key and signature of a batch entry
+	// once, so that batch checks run on points.
+	prepareBatchEntry(pk, sig []byte) (batchEntry, error)
+	// batchVerify reports whether the basic scheme signatures of entries pass
+	// the batch check with the given big-endian scalars, in one multi-pairing.
+	// msgs[i] is the message signed by entries[i].
+	batchVerify(entries []batchEntry, msgs, scalars [][]byte) bool
+	// popProve returns the proof of possession of the private key sk.
+	popProve(sk []byte) ([]byte, error)
+	// popVerify reports whether proof is a valid proof of possession for pk.
+	popVerify(pk, proof []byte) bool
+	// recoverPublicKey interpolates the threshold public key from public key
+	// shares and the BLS ids of their owners.
+	recoverPublicKey(pks [][]byte, ids [][]byte) ([]byte, error)
+	// recoverSignature interpolates the threshold signature from signature
+	// shares and the BLS ids of their owners.
+	recoverSignature(sigs [][]byte, ids [][]byte) ([]byte, error)
+}
+
+// preparedPubKey is a public key decoded by a backend. Implementations must be
+// safe for concurrent use.
+type preparedPubKey interface {
+	// verify reports whether sig is a valid signature of msg by the public key
+	// under the given scheme.
+	verify(scheme Scheme, msg, sig []byte) bool
+}
+
+// batchEntry is the public key and signature of a batch entry decoded by a
+// backend, which only accepts the entries it prepared itself.
+type batchEntry interface{}
+
+// preparedSecretKey is a private key decoded by a backend. It is not safe for
+// concurrent use with destroy.
+type preparedSecretKey interface {
+	// publicKey returns the compressed G1 public key of the private key.
+	publicKey() ([]byte, error)
+	// sign signs msg with the private key using the given scheme.
+	sign(scheme Scheme, msg []byte) ([]byte, error)
+	// destroy releases the private key. The key must not be used afterwards.
+	destroy()
+}
@@ -0,0 +1,322 @@
+//go:build cgo && !purego
+
+package bls12381
+
+import (
+	bls "github.com/dashpay/bls-signatures/go-bindings"
+)
+
+// defaultBackend is the dashpay/bls-signatures library unless the package is
+// built without cgo or with the purego build tag.
+var defaultBackend backend = cgoBackend{}
+
+var (
+	schema    = bls.NewBasicSchemeMPL()
+	popSchema = bls.NewPopSchemeMPL()
+
+	// cgoSchemas maps each Scheme to its dashpay/bls-signatures implementation.
+	cgoSchemas = [...]bls.Scheme{
+		SchemeBasic:     schema,
+		SchemeAugmented: bls.NewAugSchemeMPL(),
+		SchemePoP:       popSchema,
+	}
+)
+
+// BasicScheme returns basic bls scheme
+//
+// Deprecated: use SchemeBasic, which does not depend on cgo. BasicScheme only
+// exists in cgo builds without the purego build tag.
+func BasicScheme() *bls.BasicSchemeMPL {
+	return schema
+}
+
+// cgoBackend implements backend with the dashpay/bls-signatures C++ library.
+type cgoBackend struct{}
+
+func (cgoBackend) keyGen(seed []byte) ([]byte, error) {
+	sk, err := schema.KeyGen(seed)
+	if err != nil {
+		return nil, err
+	}
+	return sk.Serialize(), nil
+}
+
+func (cgoBackend) publicKey(sk []byte) ([]byte, error) {
+	// set modOrder flag to true so that too big random bytes will wrap around and be a valid key
+	blsPrivateKey, err := bls.PrivateKeyFromBytes(sk, true)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := blsPrivateKey.G1Element()
+	if err != nil {
+		return nil, err
+	}
+	return pk.Serialize(), nil
+}
+
+func (cgoBackend) sign(scheme Scheme, sk, msg []byte) ([]byte, error) {
+	// set modOrder flag to true so that too big random bytes will wrap around and be a valid key
+	blsPrivateKey, err := bls.PrivateKeyFromBytes(sk, true)
+	if err != nil {
+		return nil, err
+	}
+	return cgoSchemas[scheme].Sign(blsPrivateKey, msg).Serialize(), nil
+}
+
+func (cgoBackend) prepareSecretKey(sk []byte) (preparedSecretKey, error) {
+	blsPrivateKey, err := bls.PrivateKeyFromBytes(sk, true)
+	if err != nil {
+		return nil, err
+	}
+	return &cgoPreparedSecretKey{key: blsPrivateKey}, nil
+}
+
+func (cgoBackend) verify(scheme Scheme, pk, msg, sig []byte) bool {
+	publicKey, err := bls.G1ElementFromBytes(pk)
+	if err != nil {
+		return false
+	}
+	blsSignature, err := bls.G2ElementFromBytes(sig)
+	if err != nil {
+		return false
+	}
+	return cgoSchemas[scheme].Verify(publicKey, msg, blsSignature)
+}
+
+func (cgoBackend) preparePubKey(pk []byte) (preparedPubKey, error) {
+	publicKey, err := bls.G1ElementFromBytes(pk)
+	if err != nil {
+		return nil, err
+	}
+	return &cgoPreparedPubKey{element: publicKey}, nil
+}
+
+func (cgoBackend) validatePubKey(pk []byte) error {
+	_, err := bls.G1ElementFromBytes(pk)
+	return err
+}
+
+func (cgoBackend) validateSignature(sig []byte) error {
+	_, err := bls.G2ElementFromBytes(sig)
+	return err
+}
+
+func (cgoBackend) aggregatePubKeys(pks [][]byte) ([]byte, error) {
+	publicKeys, err := cgoG1Elements(pks)
+	if err != nil {
+		return nil, err
+	}
+	return schema.AggregatePubKeys(publicKeys...).Serialize(), nil
+}
+
+func (cgoBackend) aggregateSignatures(sigs [][]byte) ([]byte, error) {
+	signatures, err := cgoG2Elements(sigs)
+	if err != nil {
+		return nil, err
+	}
+	return schema.AggregateSigs(signatures...).Serialize(), nil
+}
+
+func (cgoBackend) mulPubKey(pk, scalar []byte) ([]byte, error) {
+	publicKey, err := bls.G1ElementFromBytes(pk)
+	if err != nil {
+		return nil, err
+	}
+	s, err := bls.PrivateKeyFromBytes(scalar, true)
+	if err != nil {
+		return nil, err
+	}
+	return publicKey.Mul(s).Serialize(), nil
+}
+
+func (cgoBackend) mulSignature(sig, scalar []byte) ([]byte, error) {
+	signature, err := bls.G2ElementFromBytes(sig)
+	if err != nil {
+		return nil, err
+	}
+	s, err := bls.PrivateKeyFromBytes(scalar, true)
+	if err != nil {
+		return nil, err
+	}
+	return signature.Mul(s).Serialize(), nil
+}
+
+func (cgoBackend) aggregateVerify(scheme Scheme, pks, msgs [][]byte, sig []byte) bool {
+	publicKeys, err := cgoG1Elements(pks)
+	if err != nil {
+		return false
+	}
+	signature, err := bls.G2ElementFromBytes(sig)
+	if err != nil {
+		return false
+	}
+	return cgoSchemas[scheme].AggregateVerify(publicKeys, msgs, signature)
+}
+
+func (cgoBackend) prepareBatchEntry(pk, sig []byte) (batchEntry, error) {
+	publicKey, err := bls.G1ElementFromBytes(pk)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := bls.G2ElementFromBytes(sig)
+	if err != nil {
+		return nil, err
+	}
+	return &cgoBatchEntry{pk: publicKey, sig: signature}, nil
+}
+
+func (cgoBackend) batchVerify(entries []batchEntry, msgs, scalars [][]byte) bool {
+	if len(entries) == 0 || len(entries) != len(msgs) || len(entries) != len(scalars) {
+		return false
+	}
+	var (
+		sig     *bls.G2Element
+		pks     []*bls.G1Element
+		groups  [][]byte
+		groupOf = make(map[string]int)
+	)
+	for i, e := range entries {
+		entry, ok := e.(*cgoBatchEntry)
+		if !ok {
+			return false
+		}
+		s, err := bls.PrivateKeyFromBytes(scalars[i], true)
+		if err != nil {
+			return false
+		}
+		if r := entry.sig.Mul(s); sig == nil {
+			sig = r
+		} else {
+			sig = sig.Add(r)
+		}
+		pk := entry.pk.Mul(s)
+		// entries signing the same message share one pairing
+		group, ok := groupOf[string(msgs[i])]
+		if !ok {
+			groupOf[string(msgs[i])] = len(groups)
+			groups = append(groups, msgs[i])
+			pks = append(pks, pk)
+			continue
+		}
+		pks[group] = pks[group].Add(pk)
+	}
+	return schema.AggregateVerify(pks, groups, sig)
+}
+
+func (cgoBackend) popProve(sk []byte) ([]byte, error) {
+	blsPrivateKey, err := bls.PrivateKeyFromBytes(sk, true)
+	if err != nil {
+		return nil, err
+	}
+	return popSchema.PopProve(blsPrivateKey).Serialize(), nil
+}
+
+func (cgoBackend) popVerify(pk, proof []byte) bool {
+	publicKey, err := bls.G1ElementFromBytes(pk)
+	if err != nil {
+		return false
+	}
+	signature, err := bls.G2ElementFromBytes(proof)
+	if err != nil {
+		return false
+	}
+	return popSchema.PopVerify(publicKey, signature)
+}
+
+func (cgoBackend) recoverPublicKey(pks [][]byte, ids [][]byte) ([]byte, error) {
+	publicKeyShares, err := cgoG1Elements(pks)
+	if err != nil {
+		return nil, err
+	}
+	thresholdPublicKey, err := bls.ThresholdPublicKeyRecover(publicKeyShares, cgoHashes(ids))
+	if err != nil {
+		return nil, err
+	}
+	return thresholdPublicKey.Serialize(), nil
+}
+
+func (cgoBackend) recoverSignature(sigs [][]byte, ids [][]byte) ([]byte, error) {
+	sigShares, err := cgoG2Elements(sigs)
+	if err != nil {
+		return nil, err
+	}
+	thresholdSignature, err := bls.ThresholdSignatureRecover(sigShares, cgoHashes(ids))
+	if err != nil {
+		return nil, err
+	}
+	return thresholdSignature.Serialize(), nil
+}
+
+// cgoPreparedPubKey is a public key decoded by cgoBackend.
+type cgoPreparedPubKey struct {
+	element *bls.G1Element
+}
+
+func (p *cgoPreparedPubKey) verify(scheme Scheme, msg, sig []byte) bool {
+	blsSignature, err := bls.G2ElementFromBytes(sig)
+	if err != nil {
+		return false
+	}
+	return cgoSchemas[scheme].Verify(p.element, msg, blsSignature)
+}
+
+// cgoBatchEntry is a batch entry decoded by cgoBackend.
+type cgoBatchEntry struct {
+	pk  *bls.G1Element
+	sig *bls.G2Element
+}
+
+// cgoPreparedSecretKey is a private key held by cgoBackend. The bindings free
+// the native key, which wipes it, only from a finalizer and offer no way to do
+// it earlier, so destroy can only drop the last reference to it.
+type cgoPreparedSecretKey struct {
+	key *bls.PrivateKey
+}
+
+func (k *cgoPreparedSecretKey) publicKey() ([]byte, error) {
+	pk, err := k.key.G1Element()
+	if err != nil {
+		return nil, err
+	}
+	return pk.Serialize(), nil
+}
+
+func (k *cgoPreparedSecretKey) sign(scheme Scheme, msg []byte) ([]byte, error) {
+	return cgoSchemas[scheme].Sign(k.key, msg).Serialize(), nil
+}
+
+func (k *cgoPreparedSecretKey) destroy() {
+	k.key = nil
+}
+
+func cgoHashes(ids [][]byte) []bls.Hash {
+	hashes := make([]bls.Hash, len(ids))
+	for i, id := range ids {
+		copy(hashes[i][:], id)
+	}
+	return hashes
+}
+
+func cgoG1Elements(pks [][]byte) ([]*bls.G1Element, error) {
+	elements := make([]*bls.G1Element, len(pks))
+	for i, pk := range pks {
+		element, err := bls.G1ElementFromBytes(pk)
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = element
+	}
+	return elements, nil
+}
+
+func cgoG2Elements(sigs [][]byte) ([]*bls.G2Element, error) {
+	elements := make([]*bls.G2Element, len(sigs))
+	for i, sig := range sigs {
+		element, err := bls.G2ElementFromBytes(sig)
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = element
+	}
+	return elements, nil
+}
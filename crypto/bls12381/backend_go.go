@@ -0,0 +1,456 @@
+package bls12381
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	bls12 "github.com/kilic/bls12-381"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// keyGenSalt is the HKDF salt used by KeyGen.
+	keyGenSalt = "BLS-SIG-KEYGEN-SALT-"
+	// keyGenOKMSize is the number of HKDF output bytes reduced into a private key.
+	keyGenOKMSize = 48
+)
+
+var (
+	// curveOrder is the order r of the G1, G2 and GT groups.
+	curveOrder, _ = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+	// fieldModulus is the prime p of the base field of the curve.
+	fieldModulus, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+
+	errSeedTooShort   = fmt.Errorf("seed size must be at least %d bytes", SeedSize)
+	errDuplicateBLSID = errors.New("duplicate bls id")
+	errNoShares       = errors.New("no shares to recover from")
+)
+
+// goBackend implements backend in pure Go on top of github.com/kilic/bls12-381.
+// It produces the same bytes as cgoBackend and does not need cgo.
+type goBackend struct{}
+
+func (goBackend) keyGen(seed []byte) ([]byte, error) {
+	if len(seed) < SeedSize {
+		return nil, errSeedTooShort
+	}
+	ikm := make([]byte, len(seed)+1)
+	copy(ikm, seed)
+	defer wipeBytes(ikm)
+	okm := make([]byte, keyGenOKMSize)
+	defer wipeBytes(okm)
+	kdf := hkdf.New(sha256.New, ikm, []byte(keyGenSalt), []byte{0, keyGenOKMSize})
+	if _, err := io.ReadFull(kdf, okm); err != nil {
+		return nil, err
+	}
+	sk := new(big.Int).SetBytes(okm)
+	defer wipeScalar(sk)
+	return scalarToBytes(sk.Mod(sk, curveOrder)), nil
+}
+
+func (goBackend) publicKey(sk []byte) ([]byte, error) {
+	return withScalar(sk, func(s *big.Int) ([]byte, error) {
+		g1 := bls12.NewG1()
+		return g1.ToCompressed(g1.MulScalarBig(g1.New(), g1.One(), s)), nil
+	})
+}
+
+func (goBackend) sign(scheme Scheme, sk, msg []byte) ([]byte, error) {
+	return withScalar(sk, func(s *big.Int) ([]byte, error) {
+		if scheme == SchemeAugmented {
+			g1 := bls12.NewG1()
+			msg = scheme.augment(g1.ToCompressed(g1.MulScalarBig(g1.New(), g1.One(), s)), msg)
+		}
+		return coreSign(s, msg, scheme.dst())
+	})
+}
+
+func (goBackend) prepareSecretKey(sk []byte) (preparedSecretKey, error) {
+	if len(sk) != PrivateKeySize {
+		return nil, errInvalidPrivateKeySize(len(sk))
+	}
+	return &goPreparedSecretKey{sk: sk}, nil
+}
+
+func (goBackend) verify(scheme Scheme, pk, msg, sig []byte) bool {
+	return coreVerify(pk, scheme.augment(pk, msg), sig, scheme.dst())
+}
+
+func (goBackend) preparePubKey(pk []byte) (preparedPubKey, error) {
+	publicKey, err := bls12.NewG1().FromCompressed(pk)
+	if err != nil {
+		return nil, err
+	}
+	return &goPreparedPubKey{pk: append([]byte(nil), pk...), point: publicKey}, nil
+}
+
+func (goBackend) validatePubKey(pk []byte) error {
+	_, err := bls12.NewG1().FromCompressed(pk)
+	return err
+}
+
+func (goBackend) validateSignature(sig []byte) error {
+	_, err := bls12.NewG2().FromCompressed(sig)
+	return err
+}
+
+func (goBackend) aggregatePubKeys(pks [][]byte) ([]byte, error) {
+	g1 := bls12.NewG1()
+	aggregate := g1.Zero()
+	for _, pk := range pks {
+		publicKey, err := g1.FromCompressed(pk)
+		if err != nil {
+			return nil, err
+		}
+		g1.Add(aggregate, aggregate, publicKey)
+	}
+	return g1.ToCompressed(aggregate), nil
+}
+
+func (goBackend) aggregateSignatures(sigs [][]byte) ([]byte, error) {
+	g2 := bls12.NewG2()
+	aggregate := g2.Zero()
+	for _, sig := range sigs {
+		signature, err := g2.FromCompressed(sig)
+		if err != nil {
+			return nil, err
+		}
+		g2.Add(aggregate, aggregate, signature)
+	}
+	return g2.ToCompressed(aggregate), nil
+}
+
+func (goBackend) mulPubKey(pk, scalar []byte) ([]byte, error) {
+	s, err := scalarFromBytes(scalar)
+	if err != nil {
+		return nil, err
+	}
+	g1 := bls12.NewG1()
+	publicKey, err := g1.FromCompressed(pk)
+	if err != nil {
+		return nil, err
+	}
+	return g1.ToCompressed(g1.MulScalarBig(g1.New(), publicKey, s)), nil
+}
+
+func (goBackend) mulSignature(sig, scalar []byte) ([]byte, error) {
+	s, err := scalarFromBytes(scalar)
+	if err != nil {
+		return nil, err
+	}
+	g2 := bls12.NewG2()
+	signature, err := g2.FromCompressed(sig)
+	if err != nil {
+		return nil, err
+	}
+	return g2.ToCompressed(g2.MulScalarBig(g2.New(), signature, s)), nil
+}
+
+func (goBackend) aggregateVerify(scheme Scheme, pks, msgs [][]byte, sig []byte) bool {
+	return coreAggregateVerify(scheme, scheme.dst(), pks, msgs, sig)
+}
+
+func (goBackend) prepareBatchEntry(pk, sig []byte) (batchEntry, error) {
+	publicKey, err := bls12.NewG1().FromCompressed(pk)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := bls12.NewG2().FromCompressed(sig)
+	if err != nil {
+		return nil, err
+	}
+	return &goBatchEntry{pk: publicKey, sig: signature}, nil
+}
+
+func (goBackend) batchVerify(entries []batchEntry, msgs, scalars [][]byte) bool {
+	if len(entries) == 0 || len(entries) != len(msgs) || len(entries) != len(scalars) {
+		return false
+	}
+	engine := bls12.NewEngine()
+	g1, g2 := engine.G1, engine.G2
+	var (
+		sigs    = make([]*bls12.PointG2, len(entries))
+		rs      = make([]*big.Int, len(entries))
+		groups  [][]byte
+		pks     [][]*bls12.PointG1
+		pkRs    [][]*big.Int
+		groupOf = make(map[string]int)
+	)
+	for i, e := range entries {
+		entry, ok := e.(*goBatchEntry)
+		if !ok {
+			return false
+		}
+		r, err := scalarFromBytes(scalars[i])
+		if err != nil {
+			return false
+		}
+		sigs[i], rs[i] = entry.sig, r
+		// entries signing the same message share one pairing
+		group, ok := groupOf[string(msgs[i])]
+		if !ok {
+			group = len(groups)
+			groupOf[string(msgs[i])] = group
+			groups = append(groups, msgs[i])
+			pks = append(pks, nil)
+			pkRs = append(pkRs, nil)
+		}
+		pks[group] = append(pks[group], entry.pk)
+		pkRs[group] = append(pkRs[group], r)
+	}
+	sig, err := g2.MultiExpBig(g2.New(), sigs, rs)
+	if err != nil {
+		return false
+	}
+	// e(-g1, sig) * e(pk_1, H(msg_1)) * ... * e(pk_n, H(msg_n)) == 1
+	engine.AddPairInv(g1.One(), sig)
+	for i, msg := range groups {
+		pk, err := g1.MultiExpBig(g1.New(), pks[i], pkRs[i])
+		if err != nil {
+			return false
+		}
+		h, err := hashToG2(g2, msg, SchemeBasic.dst())
+		if err != nil {
+			return false
+		}
+		engine.AddPair(pk, h)
+	}
+	return engine.Check()
+}
+
+func (goBackend) popProve(sk []byte) ([]byte, error) {
+	return withScalar(sk, func(s *big.Int) ([]byte, error) {
+		g1 := bls12.NewG1()
+		pk := g1.ToCompressed(g1.MulScalarBig(g1.New(), g1.One(), s))
+		return coreSign(s, pk, popProofDST)
+	})
+}
+
+func (goBackend) popVerify(pk, proof []byte) bool {
+	return coreVerify(pk, pk, proof, popProofDST)
+}
+
+func (goBackend) recoverPublicKey(pks [][]byte, ids [][]byte) ([]byte, error) {
+	coefficients, err := lagrangeCoefficients(ids)
+	if err != nil {
+		return nil, err
+	}
+	g1 := bls12.NewG1()
+	shares := make([]*bls12.PointG1, len(pks))
+	for i, pk := range pks {
+		if shares[i], err = g1.FromCompressed(pk); err != nil {
+			return nil, err
+		}
+	}
+	thresholdPublicKey, err := g1.MultiExpBig(g1.New(), shares, coefficients)
+	if err != nil {
+		return nil, err
+	}
+	return g1.ToCompressed(thresholdPublicKey), nil
+}
+
+func (goBackend) recoverSignature(sigs [][]byte, ids [][]byte) ([]byte, error) {
+	coefficients, err := lagrangeCoefficients(ids)
+	if err != nil {
+		return nil, err
+	}
+	g2 := bls12.NewG2()
+	shares := make([]*bls12.PointG2, len(sigs))
+	for i, sig := range sigs {
+		if shares[i], err = g2.FromCompressed(sig); err != nil {
+			return nil, err
+		}
+	}
+	thresholdSignature, err := g2.MultiExpBig(g2.New(), shares, coefficients)
+	if err != nil {
+		return nil, err
+	}
+	return g2.ToCompressed(thresholdSignature), nil
+}
+
+// coreAggregateVerify reports whether sig is the aggregate of signatures of
+// msgs[i] by pks[i], augmented as the scheme requires and hashed to G2 with the
+// domain separation tag dst.
+func coreAggregateVerify(scheme Scheme, dst string, pks, msgs [][]byte, sig []byte) bool {
+	if len(pks) == 0 || len(pks) != len(msgs) {
+		return false
+	}
+	engine := bls12.NewEngine()
+	signature, err := engine.G2.FromCompressed(sig)
+	if err != nil {
+		return false
+	}
+	// e(-g1, sig) * e(pk_1, H(msg_1)) * ... * e(pk_n, H(msg_n)) == 1
+	engine.AddPairInv(engine.G1.One(), signature)
+	for i, pk := range pks {
+		publicKey, err := engine.G1.FromCompressed(pk)
+		if err != nil {
+			return false
+		}
+		h, err := hashToG2(engine.G2, scheme.augment(pk, msgs[i]), dst)
+		if err != nil {
+			return false
+		}
+		engine.AddPair(publicKey, h)
+	}
+	return engine.Check()
+}
+
+// coreSign signs msg hashed to G2 with the domain separation tag dst.
+func coreSign(s *big.Int, msg []byte, dst string) ([]byte, error) {
+	g2 := bls12.NewG2()
+	h, err := hashToG2(g2, msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	return g2.ToCompressed(g2.MulScalarBig(g2.New(), h, s)), nil
+}
+
+// goPreparedPubKey is a public key decoded by goBackend.
+type goPreparedPubKey struct {
+	pk    []byte
+	point *bls12.PointG1
+}
+
+func (p *goPreparedPubKey) verify(scheme Scheme, msg, sig []byte) bool {
+	// the pairing engine modifies the points it is given, so it works on a copy
+	publicKey := new(bls12.PointG1).Set(p.point)
+	return coreVerifyPoint(publicKey, scheme.augment(p.pk, msg), sig, scheme.dst())
+}
+
+// goBatchEntry is a batch entry decoded by goBackend. Its points are only
+// read, so that bisection can check them again.
+type goBatchEntry struct {
+	pk  *bls12.PointG1
+	sig *bls12.PointG2
+}
+
+// goPreparedSecretKey is a private key held by goBackend. The scalar is
+// decoded for every operation and wiped right after, so that the only lasting
+// copy of the key is the buffer of the caller.
+type goPreparedSecretKey struct {
+	sk []byte
+}
+
+func (k *goPreparedSecretKey) publicKey() ([]byte, error) {
+	return goBackend{}.publicKey(k.sk)
+}
+
+func (k *goPreparedSecretKey) sign(scheme Scheme, msg []byte) ([]byte, error) {
+	return goBackend{}.sign(scheme, k.sk, msg)
+}
+
+func (k *goPreparedSecretKey) destroy() {
+	k.sk = nil
+}
+
+// coreVerify reports whether sig is a signature by pk of msg hashed to G2 with
+// the domain separation tag dst.
+func coreVerify(pk, msg, sig []byte, dst string) bool {
+	publicKey, err := bls12.NewG1().FromCompressed(pk)
+	if err != nil {
+		return false
+	}
+	return coreVerifyPoint(publicKey, msg, sig, dst)
+}
+
+// coreVerifyPoint is coreVerify with a decoded public key.
+func coreVerifyPoint(publicKey *bls12.PointG1, msg, sig []byte, dst string) bool {
+	engine := bls12.NewEngine()
+	signature, err := engine.G2.FromCompressed(sig)
+	if err != nil {
+		return false
+	}
+	h, err := hashToG2(engine.G2, msg, dst)
+	if err != nil {
+		return false
+	}
+	// e(-g1, sig) * e(pk, H(msg)) == 1
+	engine.AddPairInv(engine.G1.One(), signature)
+	engine.AddPair(publicKey, h)
+	return engine.Check()
+}
+
+// hashToG2 hashes msg to a point of G2 with the hash_to_curve suite
+// BLS12381G2_XMD:SHA-256_SSWU_RO_ of RFC 9380 and the domain separation tag dst.
+func hashToG2(g2 *bls12.G2, msg []byte, dst string) (*bls12.PointG2, error) {
+	if err := validateDST(dst); err != nil {
+		return nil, err
+	}
+	return g2.HashToCurve(msg, []byte(dst))
+}
+
+// lagrangeCoefficients returns the Lagrange basis polynomials for the given
+// BLS ids evaluated at zero. Ids are big-endian integers reduced modulo the
+// group order.
+func lagrangeCoefficients(ids [][]byte) ([]*big.Int, error) {
+	if len(ids) == 0 {
+		return nil, errNoShares
+	}
+	xs := make([]*big.Int, len(ids))
+	for i, id := range ids {
+		xs[i] = new(big.Int).SetBytes(id)
+		xs[i].Mod(xs[i], curveOrder)
+	}
+	coefficients := make([]*big.Int, len(xs))
+	for i := range xs {
+		num, den := big.NewInt(1), big.NewInt(1)
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			diff := new(big.Int).Sub(xs[j], xs[i])
+			if diff.Sign() == 0 {
+				return nil, errDuplicateBLSID
+			}
+			num.Mul(num, xs[j]).Mod(num, curveOrder)
+			den.Mul(den, diff).Mod(den, curveOrder)
+		}
+		den.ModInverse(den, curveOrder)
+		coefficients[i] = num.Mul(num, den).Mod(num, curveOrder)
+	}
+	return coefficients, nil
+}
+
+// scalarFromBytes decodes a big-endian private key, reducing it modulo the
+// group order.
+func scalarFromBytes(sk []byte) (*big.Int, error) {
+	if len(sk) != PrivateKeySize {
+		return nil, errInvalidPrivateKeySize(len(sk))
+	}
+	s := new(big.Int).SetBytes(sk)
+	// a private key is less than three times the group order, so subtracting
+	// reduces it in place, where Mod would leave a copy in a temporary buffer
+	for s.Cmp(curveOrder) >= 0 {
+		s.Sub(s, curveOrder)
+	}
+	return s, nil
+}
+
+// withScalar decodes the private key sk and calls fn with its scalar, which is
+// wiped once fn returns.
+func withScalar(sk []byte, fn func(s *big.Int) ([]byte, error)) ([]byte, error) {
+	s, err := scalarFromBytes(sk)
+	if err != nil {
+		return nil, err
+	}
+	defer wipeScalar(s)
+	return fn(s)
+}
+
+// wipeScalar overwrites the words of a secret scalar.
+func wipeScalar(s *big.Int) {
+	words := s.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	s.SetInt64(0)
+}
+
+// scalarToBytes encodes s as a big-endian private key.
+func scalarToBytes(s *big.Int) []byte {
+	return s.FillBytes(make([]byte, PrivateKeySize))
+}
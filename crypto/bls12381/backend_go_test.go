@@ -0,0 +1,48 @@
+package bls12381
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Known answers of the dashpay/bls-signatures basic scheme, as asserted by the
+// tests of its Go bindings.
+func TestGoBackendBasicSchemeVectors(t *testing.T) {
+	be := goBackend{}
+
+	sk, err := be.keyGen(bytes.Repeat([]byte{0}, SeedSize))
+	require.NoError(t, err)
+	assert.Equal(t, "4a353be3dac091a0a7e640620372f5e1e2e4401717c1e79cac6ffba8f6905604", hex.EncodeToString(sk))
+	pk, err := be.publicKey(sk)
+	require.NoError(t, err)
+	assert.Equal(t, "85695fcbc06cc4c4c9451f4dce21cbf8de3e5a13bf48f44cdbb18e2038ba7b8bb1632d7911ef1e2e08749bddbf165352", hex.EncodeToString(pk))
+	sig, err := be.sign(SchemeBasic, sk, []byte{7, 8, 9})
+	require.NoError(t, err)
+	assert.Equal(t, "b8faa6d6a3881c9fdbad803b170d70ca5cbf1e6ba5a586262df368c75acd1d1ffa3ab6ee21c71f844494659878f5eb230c958dd576b08b8564aad2ee0992e85a1e565f299cd53a285de729937f70dc176a1f01432129bb2b94d3d5031f8065a1", hex.EncodeToString(sig))
+	assert.True(t, be.verify(SchemeBasic, pk, []byte{7, 8, 9}, sig))
+	assert.False(t, be.verify(SchemeBasic, pk, []byte{7, 8}, sig))
+
+	secret := make([]byte, PrivateKeySize)
+	for i := range secret {
+		secret[i] = byte(i * 314159 % 256)
+	}
+	sig, err = be.sign(SchemeBasic, secret, []byte{3, 1, 4, 1, 5, 9})
+	require.NoError(t, err)
+	assert.Equal(t, "a402790932130f766af11ba716536683d8c4cfa51947e4f9081fedd692d6dc0cac5b904bee5ea6e25569e36d7be4ca59069a96e34b7f700758b716f9494aaa59a96e74d14a3b552a9a6bc129e717195b9d6006fd6d5cef4768c022e0f7316abf", hex.EncodeToString(sig))
+
+	_, err = be.keyGen(bytes.Repeat([]byte{8}, SeedSize-1))
+	assert.Error(t, err)
+}
+
+func TestGoBackendRecoverDuplicateIDs(t *testing.T) {
+	be := goBackend{}
+	sk := GenPrivKey()
+	pk := sk.PubKey().Bytes()
+	id := bytes.Repeat([]byte{1}, 32)
+	_, err := be.recoverPublicKey([][]byte{pk, pk}, [][]byte{id, id})
+	assert.ErrorIs(t, err, errDuplicateBLSID)
+}
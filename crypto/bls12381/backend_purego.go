@@ -0,0 +1,7 @@
+//go:build !cgo || purego
+
+package bls12381
+
+// defaultBackend is the pure Go implementation when the package is built
+// without cgo or with the purego build tag.
+var defaultBackend backend = goBackend{}
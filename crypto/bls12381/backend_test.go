@@ -0,0 +1,196 @@
+//go:build cgo && !purego
+
+package bls12381
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+// The tests in this file run the pure Go backend against the cgo backend and
+// expect both of them to produce exactly the same bytes.
+
+func TestBackendsKeyGen(t *testing.T) {
+	for i := 0; i < 16; i++ {
+		seed := crypto.CRandBytes(SeedSize + i)
+		want, err := cgoBackend{}.keyGen(seed)
+		require.NoError(t, err)
+		got, err := goBackend{}.keyGen(seed)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, "seed %X", seed)
+	}
+	_, err := goBackend{}.keyGen(make([]byte, SeedSize-1))
+	assert.Error(t, err)
+	_, err = cgoBackend{}.keyGen(make([]byte, SeedSize-1))
+	assert.Error(t, err)
+}
+
+func TestBackendsSignAndVerify(t *testing.T) {
+	backends := []backend{cgoBackend{}, goBackend{}}
+	for _, scheme := range []Scheme{SchemeBasic, SchemeAugmented, SchemePoP} {
+		for i := 0; i < 16; i++ {
+			// random bytes also cover private keys above the group order
+			sk := crypto.CRandBytes(PrivateKeySize)
+			msg := crypto.CRandBytes(i * 8)
+
+			wantPk, err := cgoBackend{}.publicKey(sk)
+			require.NoError(t, err)
+			gotPk, err := goBackend{}.publicKey(sk)
+			require.NoError(t, err)
+			require.Equal(t, wantPk, gotPk, "private key %X", sk)
+
+			wantSig, err := cgoBackend{}.sign(scheme, sk, msg)
+			require.NoError(t, err)
+			gotSig, err := goBackend{}.sign(scheme, sk, msg)
+			require.NoError(t, err)
+			require.Equal(t, wantSig, gotSig, "%s scheme, private key %X, message %X", scheme, sk, msg)
+
+			invalidSig, err := goBackend{}.sign(scheme, sk, append(msg, 1))
+			require.NoError(t, err)
+			for _, b := range backends {
+				assert.True(t, b.verify(scheme, wantPk, msg, wantSig), "%T", b)
+				assert.False(t, b.verify(scheme, wantPk, msg, invalidSig), "%T", b)
+				assert.False(t, b.verify(scheme, wantPk, append(msg, 1), wantSig), "%T", b)
+
+				prepared, err := b.preparePubKey(wantPk)
+				require.NoError(t, err)
+				assert.True(t, prepared.verify(scheme, msg, wantSig), "%T", b)
+				assert.False(t, prepared.verify(scheme, msg, invalidSig), "%T", b)
+			}
+		}
+	}
+}
+
+func TestBackendsPop(t *testing.T) {
+	sk := crypto.CRandBytes(PrivateKeySize)
+	pk, err := goBackend{}.publicKey(sk)
+	require.NoError(t, err)
+
+	want, err := cgoBackend{}.popProve(sk)
+	require.NoError(t, err)
+	got, err := goBackend{}.popProve(sk)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	for _, b := range []backend{cgoBackend{}, goBackend{}} {
+		assert.True(t, b.popVerify(pk, want), "%T", b)
+		// a signature of the public key under the PoP scheme is not a proof
+		sig, err := b.sign(SchemePoP, sk, pk)
+		require.NoError(t, err)
+		assert.False(t, b.popVerify(pk, sig), "%T", b)
+	}
+}
+
+func TestBackendsRecover(t *testing.T) {
+	msg := crypto.CRandBytes(32)
+	for _, n := range []int{2, 3, 10} {
+		t.Run(fmt.Sprintf("%d shares", n), func(t *testing.T) {
+			ids := make([][]byte, n)
+			pks := make([][]byte, n)
+			sigs := make([][]byte, n)
+			for i := 0; i < n; i++ {
+				ids[i] = crypto.RandProTxHash()
+				sk := GenPrivKey()
+				pks[i] = sk.PubKey().Bytes()
+				sig, err := sk.Sign(msg)
+				require.NoError(t, err)
+				sigs[i] = sig
+			}
+
+			wantPk, err := cgoBackend{}.recoverPublicKey(pks, ids)
+			require.NoError(t, err)
+			gotPk, err := goBackend{}.recoverPublicKey(pks, ids)
+			require.NoError(t, err)
+			assert.Equal(t, wantPk, gotPk)
+
+			wantSig, err := cgoBackend{}.recoverSignature(sigs, ids)
+			require.NoError(t, err)
+			gotSig, err := goBackend{}.recoverSignature(sigs, ids)
+			require.NoError(t, err)
+			assert.Equal(t, wantSig, gotSig)
+		})
+	}
+}
+
+func TestBackendsRejectInvalidPoints(t *testing.T) {
+	sk := GenPrivKey()
+	msg := []byte("message")
+	pk := sk.PubKey().Bytes()
+	sig, err := sk.Sign(msg)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		pk   []byte
+		sig  []byte
+	}{
+		{name: "bad public key tag", pk: append([]byte{pk[0] &^ 0x80}, pk[1:]...), sig: sig},
+		{name: "bad signature tag", pk: pk, sig: append([]byte{sig[0] &^ 0x80}, sig[1:]...)},
+		{name: "public key x not on curve", pk: mustHexToBytes("9a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaaa"), sig: sig},
+		{name: "short public key", pk: pk[1:], sig: sig},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.False(t, cgoBackend{}.verify(SchemeBasic, tc.pk, msg, tc.sig))
+			assert.False(t, goBackend{}.verify(SchemeBasic, tc.pk, msg, tc.sig))
+		})
+	}
+}
+
+func TestBackendsAggregate(t *testing.T) {
+	n := 5
+	pks := make([][]byte, n)
+	sigs := make([][]byte, n)
+	msgs := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		sk := GenPrivKey()
+		msgs[i] = crypto.CRandBytes(32)
+		pks[i] = sk.PubKey().Bytes()
+		sig, err := sk.Sign(msgs[i])
+		require.NoError(t, err)
+		sigs[i] = sig
+	}
+
+	wantPk, err := cgoBackend{}.aggregatePubKeys(pks)
+	require.NoError(t, err)
+	gotPk, err := goBackend{}.aggregatePubKeys(pks)
+	require.NoError(t, err)
+	assert.Equal(t, wantPk, gotPk)
+
+	wantSig, err := cgoBackend{}.aggregateSignatures(sigs)
+	require.NoError(t, err)
+	gotSig, err := goBackend{}.aggregateSignatures(sigs)
+	require.NoError(t, err)
+	assert.Equal(t, wantSig, gotSig)
+
+	assert.True(t, cgoBackend{}.aggregateVerify(SchemeBasic, pks, msgs, wantSig))
+	assert.True(t, goBackend{}.aggregateVerify(SchemeBasic, pks, msgs, wantSig))
+	assert.False(t, cgoBackend{}.aggregateVerify(SchemeBasic, pks[1:], msgs[1:], wantSig))
+	assert.False(t, goBackend{}.aggregateVerify(SchemeBasic, pks[1:], msgs[1:], wantSig))
+}
+
+func TestBackendsMul(t *testing.T) {
+	sk := GenPrivKey()
+	pk := sk.PubKey().Bytes()
+	sig, err := sk.Sign([]byte("message"))
+	require.NoError(t, err)
+	scalar := crypto.CRandBytes(PrivateKeySize)
+
+	wantPk, err := cgoBackend{}.mulPubKey(pk, scalar)
+	require.NoError(t, err)
+	gotPk, err := goBackend{}.mulPubKey(pk, scalar)
+	require.NoError(t, err)
+	assert.Equal(t, wantPk, gotPk)
+
+	wantSig, err := cgoBackend{}.mulSignature(sig, scalar)
+	require.NoError(t, err)
+	gotSig, err := goBackend{}.mulSignature(sig, scalar)
+	require.NoError(t, err)
+	assert.Equal(t, wantSig, gotSig)
+}
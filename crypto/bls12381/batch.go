@@ -0,0 +1,109 @@
+package bls12381
+
+import (
+	"fmt"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+// batchRandomizerSize is the number of random bytes in the scalars that
+// randomize each entry of a batch. 128 bits make forging a passing batch out of
+// invalid signatures as hard as breaking the curve.
+const batchRandomizerSize = 16
+
+var _ crypto.BatchVerifier = &BatchVerifier{}
+
+// BatchVerifier implements crypto.BatchVerifier for signatures of the basic
+// scheme.
+//
+// All entries are checked at once by verifying that
+//
+//	e(g1, r_1*sig_1 + ... + r_n*sig_n) == e(r_1*pk_1, H(msg_1)) * ... * e(r_n*pk_n, H(msg_n))
+//
+// for random scalars r_i, where the right-hand side needs one pairing per
+// distinct message. When the check fails, the batch is bisected to find the
+// invalid entries.
+type BatchVerifier struct {
+	entries  []batchEntry
+	messages [][]byte
+}
+
+// NewBatchVerifier creates an empty BatchVerifier.
+func NewBatchVerifier() *BatchVerifier {
+	return &BatchVerifier{}
+}
+
+// Add appends an entry into the BatchVerifier. The public key and signature
+// are decoded once here, so that Add fails for points not in their subgroup
+// and for the public key at infinity.
+func (b *BatchVerifier) Add(key crypto.PubKey, message, signature []byte) error {
+	pubKey, ok := key.(PubKey)
+	if !ok {
+		return fmt.Errorf("pubkey is not BLS12-381 but %T", key)
+	}
+	if len(pubKey) != PubKeySize {
+		return fmt.Errorf("public key has wrong size %d: %w", len(pubKey), ErrPubKeyInvalidSize)
+	}
+	if isInfinity(pubKey) {
+		return ErrPubKeyInfinity
+	}
+	if len(signature) != SignatureSize {
+		return fmt.Errorf("signature has wrong size %d, expected %d", len(signature), SignatureSize)
+	}
+	entry, err := defaultBackend.prepareBatchEntry(pubKey, signature)
+	if err != nil {
+		return fmt.Errorf("invalid batch entry: %w", err)
+	}
+	b.entries = append(b.entries, entry)
+	b.messages = append(b.messages, message)
+	return nil
+}
+
+// Verify verifies all the entries in the BatchVerifier, and returns
+// if every signature in the batch is valid, and a vector of bools
+// indicating the verification status of each signature (in the order
+// that signatures were added to the batch).
+// An empty batch is not valid.
+func (b *BatchVerifier) Verify() (bool, []bool) {
+	n := len(b.entries)
+	if n == 0 {
+		return false, nil
+	}
+	randomizers := make([][]byte, n)
+	for i := range randomizers {
+		randomizers[i] = batchRandomizer()
+	}
+	valid := make([]bool, n)
+	b.bisect(randomizers, valid, 0, n)
+	for _, ok := range valid {
+		if !ok {
+			return false, valid
+		}
+	}
+	return true, valid
+}
+
+// bisect records in valid[from:to] which of the entries in this range are
+// valid, splitting the range in halves as long as the batch check fails.
+func (b *BatchVerifier) bisect(randomizers [][]byte, valid []bool, from, to int) {
+	if defaultBackend.batchVerify(b.entries[from:to], b.messages[from:to], randomizers[from:to]) {
+		for i := from; i < to; i++ {
+			valid[i] = true
+		}
+		return
+	}
+	if to-from == 1 {
+		return
+	}
+	mid := from + (to-from)/2
+	b.bisect(randomizers, valid, from, mid)
+	b.bisect(randomizers, valid, mid, to)
+}
+
+// batchRandomizer returns a random non-zero scalar of batchRandomizerSize bytes.
+func batchRandomizer() []byte {
+	scalar := make([]byte, PrivateKeySize)
+	copy(scalar[PrivateKeySize-batchRandomizerSize:], crypto.CRandBytes(batchRandomizerSize))
+	scalar[PrivateKeySize-1] |= 1
+	return scalar
+}
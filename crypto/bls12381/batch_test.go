@@ -0,0 +1,72 @@
+package bls12381
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+func TestBatchVerifier(t *testing.T) {
+	const n = 8
+	sameMsg := []byte("block hash")
+	privKeys := make([]PrivKey, n)
+	msgs := make([][]byte, n)
+	sigs := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKeys[i] = GenPrivKey()
+		// half of the entries share a message, like votes for one block do
+		msgs[i] = sameMsg
+		if i%2 == 1 {
+			msgs[i] = crypto.CRandBytes(32)
+		}
+		sigs[i] = mustSign(t, privKeys[i], msgs[i])
+	}
+	newBatch := func(sigs [][]byte) *BatchVerifier {
+		bv := NewBatchVerifier()
+		for i := range sigs {
+			require.NoError(t, bv.Add(privKeys[i].PubKey(), msgs[i], sigs[i]))
+		}
+		return bv
+	}
+
+	ok, valid := newBatch(sigs).Verify()
+	assert.True(t, ok)
+	assert.Equal(t, []bool{true, true, true, true, true, true, true, true}, valid)
+
+	invalidSigs := append([][]byte{}, sigs...)
+	invalidSigs[2] = sigs[3]
+	invalidSigs[7] = mustSign(t, privKeys[7], []byte("other message"))
+	ok, valid = newBatch(invalidSigs).Verify()
+	assert.False(t, ok)
+	assert.Equal(t, []bool{true, true, false, true, true, true, true, false}, valid)
+
+	// signatures swapped between two signers of the same message sum up to
+	// the same aggregate, the randomization must catch that
+	swappedSigs := append([][]byte{}, sigs...)
+	swappedSigs[0], swappedSigs[2] = sigs[2], sigs[0]
+	ok, valid = newBatch(swappedSigs).Verify()
+	assert.False(t, ok)
+	assert.Equal(t, []bool{false, true, false, true, true, true, true, true}, valid)
+}
+
+func TestBatchVerifierAdd(t *testing.T) {
+	bv := NewBatchVerifier()
+	ok, valid := bv.Verify()
+	assert.False(t, ok)
+	assert.Empty(t, valid)
+
+	privKey := GenPrivKey()
+	sig := mustSign(t, privKey, []byte("msg"))
+	assert.Error(t, bv.Add(PubKey(make([]byte, PubKeySize-1)), []byte("msg"), sig))
+	assert.Error(t, bv.Add(privKey.PubKey(), []byte("msg"), sig[1:]))
+	assert.ErrorIs(t, bv.Add(infinityPubKey(), []byte("msg"), sig), ErrPubKeyInfinity)
+	invalidSig := append([]byte{0xbf}, sig[1:]...)
+	assert.Error(t, bv.Add(privKey.PubKey(), []byte("msg"), invalidSig), "points are decoded on Add")
+	require.NoError(t, bv.Add(privKey.PubKey(), []byte("msg"), sig))
+	ok, valid = bv.Verify()
+	assert.True(t, ok)
+	assert.Equal(t, []bool{true}, valid)
+}
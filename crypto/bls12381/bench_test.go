@@ -1,6 +1,7 @@
 package bls12381
 
 import (
+	"fmt"
 	"io"
 	"testing"
 
@@ -24,3 +25,106 @@ func BenchmarkVerification(b *testing.B) {
 	priv := GenPrivKey()
 	benchmarking.BenchmarkVerification(b, priv)
 }
+
+func BenchmarkBatchVerification(b *testing.B) {
+	for _, distinct := range []bool{false, true} {
+		for _, n := range []int{1, 8, 64} {
+			bv := NewBatchVerifier()
+			for i := 0; i < n; i++ {
+				msg := []byte("Hello, world!")
+				if distinct {
+					msg = []byte(fmt.Sprintf("Hello, world %d!", i))
+				}
+				priv := GenPrivKey()
+				sig, err := priv.Sign(msg)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if err := bv.Add(priv.PubKey(), msg, sig); err != nil {
+					b.Fatal(err)
+				}
+			}
+			name := fmt.Sprintf("sig-count-%d", n)
+			if distinct {
+				name += "-distinct-msgs"
+			}
+			b.Run(name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if ok, _ := bv.Verify(); !ok {
+						b.Fatal("batch verification failed")
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkValidatorSetVerification verifies one vote of every member of a
+// validator set per iteration, decoding the public keys every time, through the
+// cache of PubKey.VerifySignature, or with keys prepared upfront.
+func BenchmarkValidatorSetVerification(b *testing.B) {
+	const n = 16
+	msg := []byte("Hello, world!")
+	pubKeys := make([]PubKey, n)
+	prepared := make([]*PreparedPubKey, n)
+	sigs := make([][]byte, n)
+	for i := range pubKeys {
+		priv := GenPrivKey()
+		sig, err := priv.Sign(msg)
+		if err != nil {
+			b.Fatal(err)
+		}
+		pubKeys[i], sigs[i] = priv.PubKey().(PubKey), sig
+		if prepared[i], err = pubKeys[i].Prepare(); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.Run("decode", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j, pubKey := range pubKeys {
+				if !defaultBackend.verify(SchemeBasic, pubKey, msg, sigs[j]) {
+					b.Fatal("verification failed")
+				}
+			}
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j, pubKey := range pubKeys {
+				if !pubKey.VerifySignature(msg, sigs[j]) {
+					b.Fatal("verification failed")
+				}
+			}
+		}
+	})
+	b.Run("prepared", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j, pubKey := range prepared {
+				if !pubKey.VerifySignature(msg, sigs[j]) {
+					b.Fatal("verification failed")
+				}
+			}
+		}
+	})
+}
+
+// BenchmarkPubKeyDecoding measures the part of a verification that
+// BenchmarkValidatorSetVerification saves: getting the decoded public key.
+func BenchmarkPubKeyDecoding(b *testing.B) {
+	pubKey := GenPrivKey().PubKey().Bytes()
+	b.Run("decode", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := defaultBackend.preparePubKey(pubKey); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := preparedPubKeys.get(pubKey); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -5,15 +5,14 @@ import (
 	"crypto/rand"
 	"crypto/subtle"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-
-	bls "github.com/dashpay/bls-signatures/go-bindings"
+	"math/big"
 
 	"github.com/dashpay/tenderdash/crypto"
 	"github.com/dashpay/tenderdash/internal/jsontypes"
-	tmbytes "github.com/dashpay/tenderdash/libs/bytes"
 )
 
 //-------------------------------------
@@ -37,16 +36,36 @@ const (
 )
 
 var (
-	errPubKeyIsEmpty     = errors.New("public key should not be empty")
-	errPubKeyInvalidSize = errors.New("invalid public key size")
+	// ErrPubKeyIsEmpty is returned for the all-zero public key.
+	ErrPubKeyIsEmpty = errors.New("public key should not be empty")
+	// ErrPubKeyInvalidSize is returned for public keys that are not PubKeySize
+	// bytes long.
+	ErrPubKeyInvalidSize = errors.New("invalid public key size")
+	// ErrPubKeyInvalid is returned for public keys that do not decode to a
+	// valid G1 point. The errors below detail why and wrap it.
+	ErrPubKeyInvalid = errors.New("invalid public key")
+	// ErrPubKeyInvalidEncoding is returned for public keys that are not a
+	// canonical compressed encoding.
+	ErrPubKeyInvalidEncoding = fmt.Errorf("%w: malformed point encoding", ErrPubKeyInvalid)
+	// ErrPubKeyNotOnCurve is returned for public keys that are not on the curve.
+	ErrPubKeyNotOnCurve = fmt.Errorf("%w: point is not on the curve", ErrPubKeyInvalid)
+	// ErrPubKeyNotInSubgroup is returned for public keys outside of the prime
+	// order subgroup.
+	ErrPubKeyNotInSubgroup = fmt.Errorf("%w: point is not in the G1 subgroup", ErrPubKeyInvalid)
+	// ErrPubKeyInfinity is returned for the point at infinity.
+	ErrPubKeyInfinity = fmt.Errorf("%w: point at infinity", ErrPubKeyInvalid)
+	// ErrPrivKeyInvalidSize is returned for private keys that are not
+	// PrivateKeySize bytes long.
+	ErrPrivKeyInvalidSize = errors.New("invalid private key size")
+	// ErrPrivKeyInvalid is returned for private keys that are zero or not below
+	// the group order.
+	ErrPrivKeyInvalid = errors.New("invalid private key")
 
 	emptyPubKeyVal = []byte{
 		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	}
-
-	schema = bls.NewBasicSchemeMPL()
 )
 
 func init() {
@@ -54,11 +73,6 @@ func init() {
 	jsontypes.MustRegister(PrivKey{})
 }
 
-// BasicScheme returns basic bls scheme
-func BasicScheme() *bls.BasicSchemeMPL {
-	return schema
-}
-
 // PrivKey implements crypto.PrivKey.
 type PrivKey []byte
 
@@ -70,70 +84,47 @@ func (privKey PrivKey) Bytes() []byte {
 	return privKey
 }
 
-// Sign produces a signature on the provided message.
-// This assumes the privkey is wellformed in the golang format.
-// The first 32 bytes should be random,
-// corresponding to the normal bls12381 private key.
-// The latter 32 bytes should be the compressed public key.
-// If these conditions aren't met, Sign will panic or produce an
-// incorrect signature.
+// Sign signs msg with the basic scheme. It returns an error if the private
+// key has the wrong size.
 func (privKey PrivKey) Sign(msg []byte) ([]byte, error) {
-	if len(privKey.Bytes()) != PrivateKeySize {
-		panic(errInvalidPrivateKeySize(len(privKey.Bytes())))
+	if len(privKey) != PrivateKeySize {
+		return nil, errInvalidPrivateKeySize(len(privKey))
 	}
-	// set modOrder flag to true so that too big random bytes will wrap around and be a valid key
-	blsPrivateKey, err := bls.PrivateKeyFromBytes(privKey, true)
-	if err != nil {
-		return nil, err
-	}
-	sig := schema.Sign(blsPrivateKey, msg)
-	serializedSignature := sig.Serialize()
-	// fmt.Printf("signature %X created for msg %X with key %X\n", serializedSignature, msg, privKey.PubKey().Bytes())
-	return serializedSignature, nil
+	return defaultBackend.sign(SchemeBasic, privKey, msg)
 }
 
-// SignDigest produces a signature on the provided message.
-// This assumes the privkey is wellformed in the golang format.
-// The first 32 bytes should be random,
-// corresponding to the normal bls12381 private key.
-// The latter 32 bytes should be the compressed public key.
-// If these conditions aren't met, Sign will panic or produce an
-// incorrect signature.
+// SignDigest signs the digest msg with the basic scheme. It returns an error
+// if the private key has the wrong size.
 func (privKey PrivKey) SignDigest(msg []byte) ([]byte, error) {
-	if len(privKey.Bytes()) != PrivateKeySize {
-		panic(errInvalidPrivateKeySize(len(privKey.Bytes())))
-	}
-	// set modOrder flag to true so that too big random bytes will wrap around and be a valid key
-	blsPrivateKey, err := bls.PrivateKeyFromBytes(privKey, true)
-	if err != nil {
-		return nil, err
+	if len(privKey) != PrivateKeySize {
+		return nil, errInvalidPrivateKeySize(len(privKey))
 	}
-	sig := schema.Sign(blsPrivateKey, msg)
-	serializedSignature := sig.Serialize()
-	// fmt.Printf("signature %X created for msg %X with key %X\n", serializedSignature, msg, privKey.PubKey().Bytes())
-	return serializedSignature, nil
+	return defaultBackend.sign(SchemeBasic, privKey, msg)
 }
 
 // PubKey gets the corresponding public key from the private key.
 //
-// Panics if the private key is not initialized.
+// Panics if the private key is not initialized. Use TryPubKey for keys that
+// come from outside the program.
 func (privKey PrivKey) PubKey() crypto.PubKey {
-	if len(privKey.Bytes()) != PrivateKeySize {
-		panic(errInvalidPrivateKeySize(len(privKey.Bytes())))
+	pubKey, err := privKey.TryPubKey()
+	if err != nil {
+		panic(err)
 	}
+	return pubKey
+}
 
-	// set modOrder flag to true so that too big random bytes will wrap around and be a valid key
-	blsPrivateKey, err := bls.PrivateKeyFromBytes(privKey, true)
-	if err != nil {
-		// should probably change method sign to return an error but since
-		// that's not available just panic...
-		panic("bad key")
+// TryPubKey gets the corresponding public key from the private key, or returns
+// an error if the private key is malformed.
+func (privKey PrivKey) TryPubKey() (PubKey, error) {
+	if len(privKey) != PrivateKeySize {
+		return nil, errInvalidPrivateKeySize(len(privKey))
 	}
-	pk, err := blsPrivateKey.G1Element()
+	pk, err := defaultBackend.publicKey(privKey)
 	if err != nil {
-		panic(fmt.Errorf("couldn't retrieve a public key from bls private key: %w", err))
+		return nil, fmt.Errorf("couldn't retrieve a public key from bls private key: %w", err)
 	}
-	return PubKey(pk.Serialize())
+	return PubKey(pk), nil
 }
 
 // Equals - you probably don't need to use this.
@@ -146,6 +137,12 @@ func (privKey PrivKey) Equals(other crypto.PrivKey) bool {
 	return false
 }
 
+// Wipe overwrites the private key with zeros. It only wipes the bytes the
+// slice refers to, so copies made earlier are left intact.
+func (privKey PrivKey) Wipe() {
+	wipeBytes(privKey)
+}
+
 func (privKey PrivKey) Type() string {
 	return KeyType
 }
@@ -169,11 +166,11 @@ func genPrivKey(rand io.Reader) PrivKey {
 	if err != nil {
 		panic(err)
 	}
-	sk, err := schema.KeyGen(seed)
+	sk, err := defaultBackend.keyGen(seed)
 	if err != nil {
 		panic(err)
 	}
-	return sk.Serialize()
+	return sk
 }
 
 // GenPrivKeyFromSecret hashes the secret with SHA2, and uses
@@ -182,88 +179,45 @@ func genPrivKey(rand io.Reader) PrivKey {
 // if it's derived from user input.
 func GenPrivKeyFromSecret(secret []byte) PrivKey {
 	seed := crypto.Checksum(secret) // Not Ripemd160 because we want 32 bytes.
-	sk, err := schema.KeyGen(seed)
+	sk, err := defaultBackend.keyGen(seed)
 	if err != nil {
 		panic(err)
 	}
-	return sk.Serialize()
+	return sk
 }
 
-func ReverseProTxHashes(proTxHashes []crypto.ProTxHash) []crypto.ProTxHash {
-	reversedProTxHashes := make([]crypto.ProTxHash, len(proTxHashes))
-	for i := 0; i < len(proTxHashes); i++ {
-		reversedProTxHashes[i] = proTxHashes[i].ReverseBytes()
+// RecoverThresholdPublicKeyFromPublicKeys recovers the threshold public key of
+// a quorum from the public key shares of its members with the given BLS ids.
+func RecoverThresholdPublicKeyFromPublicKeys(publicKeys []crypto.PubKey, blsIDs []BLSID) (crypto.PubKey, error) {
+	if len(publicKeys) != len(blsIDs) {
+		return nil, errors.New("the length of the public keys must match the length of the blsIDs")
 	}
-	return reversedProTxHashes
-}
-
-func RecoverThresholdPublicKeyFromPublicKeys(publicKeys []crypto.PubKey, blsIds [][]byte) (crypto.PubKey, error) {
 	// if there is only 1 key use it
 	if len(publicKeys) == 1 {
 		return publicKeys[0], nil
 	}
-	publicKeyShares := make([]*bls.G1Element, len(publicKeys))
-	hashes := make([]bls.Hash, len(publicKeys))
-	// Create and validate sigShares for each member and populate BLS-IDs from members into ids
+	publicKeyShares := make([][]byte, len(publicKeys))
 	for i, publicKey := range publicKeys {
-		publicKeyShare, err := bls.G1ElementFromBytes(publicKey.Bytes())
-		if err != nil {
-			return nil, fmt.Errorf("error recovering public key share from bytes %X (size %d - proTxHash %X): %w",
-				publicKey.Bytes(), len(publicKey.Bytes()), blsIds[i], err)
-		}
-		publicKeyShares[i] = publicKeyShare
+		publicKeyShares[i] = publicKey.Bytes()
 	}
-
-	for i, blsID := range blsIds {
-		if len(blsID) != crypto.HashSize {
-			return nil, fmt.Errorf("blsID incorrect size in public key recovery, expected 32 bytes (got %d)", len(blsID))
-		}
-		var hash bls.Hash
-		copy(hash[:], tmbytes.Reverse(blsID))
-		hashes[i] = hash
-	}
-
-	thresholdPublicKey, err := bls.ThresholdPublicKeyRecover(publicKeyShares, hashes)
+	thresholdPublicKey, err := defaultBackend.recoverPublicKey(publicKeyShares, blsIDsBytes(blsIDs))
 	if err != nil {
 		return nil, fmt.Errorf("error recovering threshold public key from shares: %w", err)
 	}
-	return PubKey(thresholdPublicKey.Serialize()), nil
+	return PubKey(thresholdPublicKey), nil
 }
 
-// RecoverThresholdSignatureFromShares BLS Ids are the Pro_tx_hashes from validators
-func RecoverThresholdSignatureFromShares(sigSharesData [][]byte, blsIds [][]byte) ([]byte, error) {
-	sigShares := make([]*bls.G2Element, len(sigSharesData))
-	hashes := make([]bls.Hash, len(sigSharesData))
-	if len(sigSharesData) != len(blsIds) {
-		return nil, errors.New("the length of the signature shares must match the length of the blsIds")
+// RecoverThresholdSignatureFromShares recovers the threshold signature from the
+// signature shares of the quorum members with the given BLS ids.
+func RecoverThresholdSignatureFromShares(sigSharesData [][]byte, blsIDs []BLSID) ([]byte, error) {
+	if len(sigSharesData) != len(blsIDs) {
+		return nil, errors.New("the length of the signature shares must match the length of the blsIDs")
 	}
 	// if there is only 1 share use it
 	if len(sigSharesData) == 1 {
 		return sigSharesData[0], nil
 	}
-	// Create and validate sigShares for each member and populate BLS-IDs from members into ids
-	for i, sigShareData := range sigSharesData {
-		sigShare, err := bls.G2ElementFromBytes(sigShareData)
-		if err != nil {
-			return nil, err
-		}
-		sigShares[i] = sigShare
-	}
-
-	for i, blsID := range blsIds {
-		if len(blsID) != crypto.HashSize {
-			return nil, fmt.Errorf("blsID incorrect size in signature recovery, expected 32 bytes (got %d)", len(blsID))
-		}
-		var hash bls.Hash
-		copy(hash[:], tmbytes.Reverse(blsID))
-		hashes[i] = hash
-	}
-
-	thresholdSignature, err := bls.ThresholdSignatureRecover(sigShares, hashes)
-	if err != nil {
-		return nil, err
-	}
-	return thresholdSignature.Serialize(), nil
+	return defaultBackend.recoverSignature(sigSharesData, blsIDsBytes(blsIDs))
 }
 
 //-------------------------------------
@@ -277,11 +231,24 @@ type PubKey []byte
 func (PubKey) TypeTag() string { return PubKeyName }
 
 // Address is the SHA256-20 of the raw pubkey bytes.
+//
+// Panics if the public key has the wrong size. Use TryAddress for keys that
+// come from outside the program.
 func (pubKey PubKey) Address() crypto.Address {
+	address, err := pubKey.TryAddress()
+	if err != nil {
+		panic(err)
+	}
+	return address
+}
+
+// TryAddress is like Address but returns an error if the public key has the
+// wrong size.
+func (pubKey PubKey) TryAddress() (crypto.Address, error) {
 	if len(pubKey) != PubKeySize {
-		panic("pubkey is incorrect size")
+		return nil, fmt.Errorf("public key has wrong size %d: %w", len(pubKey), ErrPubKeyInvalidSize)
 	}
-	return crypto.AddressHash(pubKey)
+	return crypto.AddressHash(pubKey), nil
 }
 
 // Bytes returns the PubKey byte format.
@@ -290,49 +257,20 @@ func (pubKey PubKey) Bytes() []byte {
 }
 
 func (pubKey PubKey) VerifySignatureDigest(hash []byte, sig []byte) bool {
-	// make sure we use the same algorithm to sign
-	if len(sig) == 0 {
-		//  fmt.Printf("bls verifying error (signature empty) from message %X with key %X\n", msg, pubKey.Bytes())
-		return false
-	}
-	if len(sig) != SignatureSize {
-		// fmt.Printf("bls verifying error (signature size) sig %X from message %X with key %X\n", sig, msg, pubKey.Bytes())
-		return false
-	}
-	publicKey, err := bls.G1ElementFromBytes(pubKey)
-	if err != nil {
-		return false
-	}
-	blsSignature, err := bls.G2ElementFromBytes(sig)
-	if err != nil {
-		// fmt.Printf("bls verifying error (blsSignature) sig %X from message %X with key %X\n", sig, msg, pubKey.Bytes())
-		return false
-	}
-
-	return schema.Verify(publicKey, hash, blsSignature)
+	return pubKey.VerifySignature(hash, sig)
 }
 
+// VerifySignature reports whether sig is a basic scheme signature of msg by the
+// public key. The public key at infinity never verifies, see Scheme.Verify.
 func (pubKey PubKey) VerifySignature(msg []byte, sig []byte) bool {
 	// make sure we use the same algorithm to sign
 	if len(sig) == 0 {
-		//  fmt.Printf("bls verifying error (signature empty) from message %X with key %X\n", msg, pubKey.Bytes())
 		return false
 	}
-	if len(sig) != SignatureSize {
-		// fmt.Printf("bls verifying error (signature size) sig %X from message %X with key %X\n", sig, msg, pubKey.Bytes())
+	if len(sig) != SignatureSize || isInfinity(pubKey) {
 		return false
 	}
-	publicKey, err := bls.G1ElementFromBytes(pubKey)
-	if err != nil {
-		// fmt.Printf("bls verifying error (publicKey) sig %X from message %X with key %X\n", sig, msg, pubKey.Bytes())
-		return false
-	}
-	sig1, err := bls.G2ElementFromBytes(sig)
-	if err != nil {
-		// fmt.Printf("bls verifying error (blsSignature) sig %X from message %X with key %X\n", sig, msg, pubKey.Bytes())
-		return false
-	}
-	return schema.Verify(publicKey, msg, sig1)
+	return preparedPubKeys.verify(SchemeBasic, pubKey, msg, sig)
 }
 
 func (pubKey PubKey) String() string {
@@ -360,18 +298,68 @@ func (pubKey PubKey) Equals(other crypto.PubKey) bool {
 	return false
 }
 
-// Validate validates a public key value
+// Validate validates a public key value: it must be the compressed encoding of
+// a point of the G1 subgroup other than the point at infinity.
 func (pubKey PubKey) Validate() error {
+	if err := pubKey.validateEncoding(); err != nil {
+		return err
+	}
+	// the encoding is otherwise valid, so only the subgroup check can fail
+	if err := defaultBackend.validatePubKey(pubKey); err != nil {
+		return ErrPubKeyNotInSubgroup
+	}
+	return nil
+}
+
+// validateEncoding runs the checks of Validate that do not decode the point,
+// leaving out the subgroup check.
+func (pubKey PubKey) validateEncoding() error {
 	size := len(pubKey)
 	if size != PubKeySize {
-		return fmt.Errorf("public key has wrong size %d: %w", size, errPubKeyInvalidSize)
+		return fmt.Errorf("public key has wrong size %d: %w", size, ErrPubKeyInvalidSize)
 	}
 	if bytes.Equal(pubKey, emptyPubKeyVal) {
-		return errPubKeyIsEmpty
+		return ErrPubKeyIsEmpty
+	}
+	if pubKey[0]&0x80 == 0 {
+		return ErrPubKeyInvalidEncoding
+	}
+	if pubKey[0]&0x40 != 0 {
+		if isInfinity(pubKey) {
+			return ErrPubKeyInfinity
+		}
+		return ErrPubKeyInvalidEncoding
+	}
+	x := new(big.Int).SetBytes(append([]byte{pubKey[0] & 0x1f}, pubKey[1:]...))
+	if x.Cmp(fieldModulus) >= 0 {
+		return ErrPubKeyInvalidEncoding
+	}
+	// y^2 = x^3 + 4 must have a solution
+	y2 := new(big.Int).Exp(x, big.NewInt(3), fieldModulus)
+	y2.Add(y2, big.NewInt(4)).Mod(y2, fieldModulus)
+	if big.Jacobi(y2, fieldModulus) < 0 {
+		return ErrPubKeyNotOnCurve
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes a public key and validates it.
+func (pubKey *PubKey) UnmarshalJSON(data []byte) error {
+	var b []byte
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	if b == nil {
+		*pubKey = nil
+		return nil
+	}
+	if err := PubKey(b).Validate(); err != nil {
+		return err
 	}
+	*pubKey = b
 	return nil
 }
 
 func errInvalidPrivateKeySize(size int) error {
-	return fmt.Errorf("incorrect private key %d bytes but expected %d bytes", size, PrivateKeySize)
+	return fmt.Errorf("incorrect private key %d bytes but expected %d bytes: %w", size, PrivateKeySize, ErrPrivKeyInvalidSize)
 }
@@ -131,11 +131,13 @@ func TestRecoverThresholdPublicKeyFromPublicKeys(t *testing.T) {
 	}
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("test-case #%d", i), func(t *testing.T) {
-			proTxHashes := make([][]byte, tc.n)
+			// these ids are in the byte order of dashd's uint256
+			blsIDs := make([]BLSID, tc.n)
 			for i, s := range tc.proTxHashes {
 				data, err := hex.DecodeString(s)
 				require.NoError(t, err)
-				proTxHashes[i] = tmbytes.Reverse(data)
+				blsIDs[i], err = BLSIDFromInternalBytes(data)
+				require.NoError(t, err)
 			}
 			privateKeys := make([]crypto.PrivKey, tc.n)
 			for i, s := range tc.skShares {
@@ -152,7 +154,7 @@ func TestRecoverThresholdPublicKeyFromPublicKeys(t *testing.T) {
 				sk := privateKeys[i]
 				require.Equal(t, sk.PubKey().Bytes(), pk.Bytes())
 			}
-			thresholdPublicKey, err := RecoverThresholdPublicKeyFromPublicKeys(publicKeys, proTxHashes)
+			thresholdPublicKey, err := RecoverThresholdPublicKeyFromPublicKeys(publicKeys, blsIDs)
 			require.NoError(t, err)
 			encodedThresholdPublicKey := base64.StdEncoding.EncodeToString(thresholdPublicKey.Bytes())
 			require.Equal(t, tc.pkThreshold, encodedThresholdPublicKey)
@@ -379,19 +381,33 @@ func TestRecoverThresholdSignatureFromSharesCaseStudy(t *testing.T) {
 		},
 	}
 	for i, tc := range testCases {
+		tc := tc
 		t.Run(fmt.Sprintf("test-case #%d", i), func(t *testing.T) {
 			t.Parallel()
 			var err error
-			proTxHashes := mustHexesToBytes(tc.proTxHashes...)
+			blsIDs := make([]BLSID, len(tc.proTxHashes))
+			for i, proTxHash := range mustHexesToBytes(tc.proTxHashes...) {
+				blsIDs[i], err = BLSIDFromProTxHash(proTxHash)
+				require.NoError(t, err)
+			}
 			sigShares := mustHexesToBytes(tc.sigShares...)
 			msg := mustHexToBytes(tc.msg)
-			thresholdSignature, err := RecoverThresholdSignatureFromShares(sigShares, proTxHashes)
+			thresholdSignature, err := RecoverThresholdSignatureFromShares(sigShares, blsIDs)
 			require.NoError(t, err, "should be able to recover threshold signature")
 			thresholdPublicKeyBytes := mustHexToBytes(tc.thresholdPubKey)
 			require.NoError(t, err, "should be able to decode thresholdPublicKeyBytes")
 			thresholdPublicKey := PubKey(thresholdPublicKeyBytes)
 			result := thresholdPublicKey.VerifySignatureDigest(msg, thresholdSignature)
 			require.True(t, result, "signature should be verified")
+
+			// the proTxHashes taken as ids in dashd's byte order are other members
+			for i, blsID := range blsIDs {
+				blsIDs[i], err = BLSIDFromInternalBytes(blsID.ProTxHash())
+				require.NoError(t, err)
+			}
+			thresholdSignature, err = RecoverThresholdSignatureFromShares(sigShares, blsIDs)
+			require.NoError(t, err)
+			require.False(t, thresholdPublicKey.VerifySignatureDigest(msg, thresholdSignature))
 		})
 	}
 }
@@ -446,3 +462,13 @@ func mustHexesToBytes(hexes ...string) [][]byte {
 	}
 	return ret
 }
+
+func TestVerifyInfinityPubKey(t *testing.T) {
+	pubKey := PubKey(append([]byte{0xc0}, make([]byte, PubKeySize-1)...))
+	sig := append([]byte{0xc0}, make([]byte, SignatureSize-1)...)
+	msg := []byte("any message")
+	assert.False(t, pubKey.VerifySignature(msg, sig))
+	for _, scheme := range []Scheme{SchemeBasic, SchemeAugmented, SchemePoP} {
+		assert.False(t, scheme.Verify(pubKey, msg, sig), scheme)
+	}
+}
@@ -0,0 +1,88 @@
+package bls12381
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/dashpay/tenderdash/crypto"
+	tmbytes "github.com/dashpay/tenderdash/libs/bytes"
+)
+
+// BLSIDSize is the size of a BLS id.
+const BLSIDSize = crypto.HashSize
+
+// BLSID is the id of a quorum member in threshold operations, that is the
+// point its key share is evaluated at. It holds the bytes of the id in the
+// order dashd stores the uint256 behind CBLSId, which is the reverse of the
+// order proTxHashes are displayed and kept in. Build it with
+// BLSIDFromProTxHash or BLSIDFromInternalBytes, which make the byte order of
+// the source explicit.
+type BLSID [BLSIDSize]byte
+
+// BLSIDFromProTxHash returns the BLS id of the quorum member with the given
+// proTxHash.
+func BLSIDFromProTxHash(proTxHash crypto.ProTxHash) (BLSID, error) {
+	if err := crypto.ProTxHashValidate(proTxHash); err != nil {
+		return BLSID{}, err
+	}
+	var id BLSID
+	copy(id[:], tmbytes.Reverse(proTxHash))
+	return id, nil
+}
+
+// BLSIDFromInternalBytes returns the BLS id held by b, in the byte order of
+// dashd's uint256, as BLSID.Bytes returns it.
+func BLSIDFromInternalBytes(b []byte) (BLSID, error) {
+	if len(b) != BLSIDSize {
+		return BLSID{}, fmt.Errorf("blsID incorrect size, expected %d bytes (got %d)", BLSIDSize, len(b))
+	}
+	var id BLSID
+	copy(id[:], b)
+	return id, nil
+}
+
+// BLSIDsFromProTxHashes returns the BLS ids of the quorum members with the
+// given proTxHashes, in the same order.
+func BLSIDsFromProTxHashes(proTxHashes []crypto.ProTxHash) ([]BLSID, error) {
+	ids := make([]BLSID, len(proTxHashes))
+	for i, proTxHash := range proTxHashes {
+		id, err := BLSIDFromProTxHash(proTxHash)
+		if err != nil {
+			return nil, fmt.Errorf("proTxHash %d: %w", i, err)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// Bytes returns a copy of the id in the byte order of dashd's uint256.
+func (id BLSID) Bytes() []byte {
+	return append([]byte(nil), id[:]...)
+}
+
+// ProTxHash returns the proTxHash the id is derived from.
+func (id BLSID) ProTxHash() crypto.ProTxHash {
+	return tmbytes.Reverse(id[:])
+}
+
+// String returns the id as the hex proTxHash it is derived from.
+func (id BLSID) String() string {
+	return id.ProTxHash().String()
+}
+
+// scalar returns the point the id stands for in the threshold polynomials:
+// dashd reads the uint256 bytes as a big-endian integer modulo the group
+// order.
+func (id BLSID) scalar() *big.Int {
+	x := new(big.Int).SetBytes(id[:])
+	return x.Mod(x, curveOrder)
+}
+
+// blsIDsBytes returns the ids as the hashes the backends interpolate at.
+func blsIDsBytes(ids []BLSID) [][]byte {
+	b := make([][]byte, len(ids))
+	for i, id := range ids {
+		b[i] = id.Bytes()
+	}
+	return b
+}
@@ -0,0 +1,70 @@
+package bls12381
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+func TestBLSIDFromProTxHash(t *testing.T) {
+	// dashd builds the CBLSId of a member from the uint256 of its proTxHash,
+	// whose bytes are the reverse of the displayed hash, and reads them as a
+	// big-endian integer modulo the group order
+	testCases := []struct {
+		proTxHash string
+		internal  string
+		scalar    string
+	}{
+		{
+			proTxHash: "FDC09407DA9473CDC5E5AFCBB55712C95765343B2AF900B28BE4004E69CEDBB3",
+			internal:  "B3DBCE694E00E48BB200F92A3B346557C91257B5CBAFE5C5CD7394DA0794C0FD",
+			scalar:    "3FEE2716246367437EC7212231928D527554B3B2CBB189C6CD7394DB0794C0FC",
+		},
+		{
+			proTxHash: "6DA069138E905FCF845D2E92979086E2BF89BA25D50E1C59799CBF4D2F2A9D01",
+			internal:  "019D2A2F4DBF9C79591C0ED525BA89BFE2869097922E5D84CF5F908E1369A06D",
+			scalar:    "019D2A2F4DBF9C79591C0ED525BA89BFE2869097922E5D84CF5F908E1369A06D",
+		},
+	}
+	for _, tc := range testCases {
+		proTxHash := crypto.ProTxHash(mustHexToBytes(tc.proTxHash))
+		id, err := BLSIDFromProTxHash(proTxHash)
+		require.NoError(t, err)
+		assert.Equal(t, mustHexToBytes(tc.internal), id.Bytes())
+		assert.Equal(t, proTxHash, id.ProTxHash())
+		assert.Equal(t, tc.proTxHash, id.String())
+		assert.Equal(t, mustHexToBytes(tc.scalar), scalarToBytes(id.scalar()))
+
+		fromInternal, err := BLSIDFromInternalBytes(mustHexToBytes(tc.internal))
+		require.NoError(t, err)
+		assert.Equal(t, id, fromInternal)
+
+		b := id.Bytes()
+		b[0]++
+		assert.Equal(t, mustHexToBytes(tc.internal), id.Bytes(), "Bytes returns a copy")
+	}
+
+	_, err := BLSIDFromProTxHash(crypto.RandProTxHash()[1:])
+	assert.Error(t, err)
+	_, err = BLSIDFromInternalBytes(make([]byte, BLSIDSize+1))
+	assert.Error(t, err)
+	_, err = BLSIDsFromProTxHashes([]crypto.ProTxHash{crypto.RandProTxHash(), nil})
+	assert.Error(t, err)
+
+	proTxHashes := crypto.RandProTxHashes(3)
+	ids, err := BLSIDsFromProTxHashes(proTxHashes)
+	require.NoError(t, err)
+	for i, id := range ids {
+		assert.Equal(t, proTxHashes[i], id.ProTxHash())
+	}
+}
+
+func mustBLSID(t *testing.T, proTxHash crypto.ProTxHash) BLSID {
+	t.Helper()
+	id, err := BLSIDFromProTxHash(proTxHash)
+	require.NoError(t, err)
+	return id
+}
@@ -0,0 +1,230 @@
+package bls12381
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	bls12 "github.com/kilic/bls12-381"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+// ethTestsDir holds BLS test vectors in the format of the Ethereum consensus
+// specs, see testdata/README.md. They use the PoP scheme.
+const ethTestsDir = "testdata/eth"
+
+// ethKnownDifferences lists the cases where this package deliberately differs
+// from the Ethereum specs, by handler and case name.
+var ethKnownDifferences = map[string]string{
+	"deserialization_G2/deserialization_succeeds_infinity_with_true_b_flag": "Signature.Validate rejects the point at infinity",
+}
+
+// hexBytes decodes hex strings of the test vectors, with or without 0x prefix.
+type hexBytes []byte
+
+func (h *hexBytes) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return err
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return err
+	}
+	*h = b
+	return nil
+}
+
+func (h hexBytes) MarshalYAML() (interface{}, error) {
+	return "0x" + hex.EncodeToString(h), nil
+}
+
+// ethHandlers run the cases of every handler against the public API of the
+// package and return the output to compare with the expected one.
+var ethHandlers = map[string]func(t *testing.T, input *yaml.Node) interface{}{
+	"sign": func(t *testing.T, input *yaml.Node) interface{} {
+		var in struct {
+			Privkey hexBytes `yaml:"privkey"`
+			Message hexBytes `yaml:"message"`
+		}
+		require.NoError(t, input.Decode(&in))
+		privKey, err := ParsePrivKey(in.Privkey)
+		if err != nil {
+			return nil
+		}
+		sig, err := SchemePoP.Sign(privKey, in.Message)
+		if err != nil {
+			return nil
+		}
+		return hexBytes(sig)
+	},
+	"verify": func(t *testing.T, input *yaml.Node) interface{} {
+		var in struct {
+			Pubkey    hexBytes `yaml:"pubkey"`
+			Message   hexBytes `yaml:"message"`
+			Signature hexBytes `yaml:"signature"`
+		}
+		require.NoError(t, input.Decode(&in))
+		return SchemePoP.Verify(PubKey(in.Pubkey), in.Message, in.Signature)
+	},
+	"aggregate": func(t *testing.T, input *yaml.Node) interface{} {
+		var sigs []hexBytes
+		require.NoError(t, input.Decode(&sigs))
+		aggregate, err := AggregateSignatures(toByteSlices(sigs))
+		if err != nil {
+			return nil
+		}
+		return hexBytes(aggregate)
+	},
+	"fast_aggregate_verify": func(t *testing.T, input *yaml.Node) interface{} {
+		var in struct {
+			Pubkeys   []hexBytes `yaml:"pubkeys"`
+			Message   hexBytes   `yaml:"message"`
+			Signature hexBytes   `yaml:"signature"`
+		}
+		require.NoError(t, input.Decode(&in))
+		return PopFastAggregateVerify(toPubKeys(in.Pubkeys), in.Message, in.Signature)
+	},
+	"aggregate_verify": func(t *testing.T, input *yaml.Node) interface{} {
+		var in struct {
+			Pubkeys   []hexBytes `yaml:"pubkeys"`
+			Messages  []hexBytes `yaml:"messages"`
+			Signature hexBytes   `yaml:"signature"`
+		}
+		require.NoError(t, input.Decode(&in))
+		return SchemePoP.AggregateVerify(toPubKeys(in.Pubkeys), toByteSlices(in.Messages), in.Signature)
+	},
+	"deserialization_G1": func(t *testing.T, input *yaml.Node) interface{} {
+		var in struct {
+			Pubkey hexBytes `yaml:"pubkey"`
+		}
+		require.NoError(t, input.Decode(&in))
+		_, err := ParsePubKey(in.Pubkey)
+		return err == nil
+	},
+	"deserialization_G2": func(t *testing.T, input *yaml.Node) interface{} {
+		var in struct {
+			Signature hexBytes `yaml:"signature"`
+		}
+		require.NoError(t, input.Decode(&in))
+		return Signature(in.Signature).Validate() == nil
+	},
+}
+
+func TestEthConformance(t *testing.T) {
+	for handler, run := range ethHandlers {
+		handler, run := handler, run
+		files, err := filepath.Glob(filepath.Join(ethTestsDir, handler, "*.yaml"))
+		require.NoError(t, err)
+		require.NotEmpty(t, files, handler)
+		for _, file := range files {
+			file := file
+			name := handler + "/" + strings.TrimSuffix(filepath.Base(file), ".yaml")
+			t.Run(name, func(t *testing.T) {
+				var tc struct {
+					Input  yaml.Node `yaml:"input"`
+					Output yaml.Node `yaml:"output"`
+				}
+				data, err := os.ReadFile(file)
+				require.NoError(t, err)
+				require.NoError(t, yaml.Unmarshal(data, &tc))
+
+				got := run(t, &tc.Input)
+				want := decodeEthOutput(t, &tc.Output, got)
+				if reason, ok := ethKnownDifferences[name]; ok {
+					assert.NotEqual(t, want, got, "known difference no longer applies: %s", reason)
+					return
+				}
+				assert.Equal(t, want, got)
+			})
+		}
+	}
+}
+
+// decodeEthOutput decodes the expected output of a case into the type of the
+// output of its handler.
+func decodeEthOutput(t *testing.T, output *yaml.Node, got interface{}) interface{} {
+	t.Helper()
+	if output.Tag == "!!null" {
+		return nil
+	}
+	switch got.(type) {
+	case bool:
+		var want bool
+		require.NoError(t, output.Decode(&want))
+		return want
+	default:
+		var want hexBytes
+		require.NoError(t, output.Decode(&want))
+		return want
+	}
+}
+
+// hashToCurveVectors is the format of the hash_to_curve test vectors of RFC
+// 9380.
+type hashToCurveVectors struct {
+	Ciphersuite string `json:"ciphersuite"`
+	DST         string `json:"dst"`
+	Vectors     []struct {
+		Msg string `json:"msg"`
+		P   struct {
+			X string `json:"x"`
+			Y string `json:"y"`
+		} `json:"P"`
+	} `json:"vectors"`
+}
+
+// TestHashToG2KnownAnswers checks hashing to G2 against the test vectors of the
+// BLS12381G2_XMD:SHA-256_SSWU_RO_ suite, RFC 9380, appendix J.10.1.
+func TestHashToG2KnownAnswers(t *testing.T) {
+	data, err := os.ReadFile("testdata/hash_to_curve/BLS12381G2_XMD_SHA-256_SSWU_RO_.json")
+	require.NoError(t, err)
+	var suite hashToCurveVectors
+	require.NoError(t, json.Unmarshal(data, &suite))
+	require.Equal(t, "BLS12381G2_XMD:SHA-256_SSWU_RO_", suite.Ciphersuite)
+	require.NotEmpty(t, suite.Vectors)
+
+	for _, v := range suite.Vectors {
+		v := v
+		t.Run(fmt.Sprintf("%q", v.Msg), func(t *testing.T) {
+			g2 := bls12.NewG2()
+			p, err := hashToG2(g2, []byte(v.Msg), suite.DST)
+			require.NoError(t, err)
+			// coordinates are written as "c0,c1", the uncompressed encoding is
+			// x.c1 || x.c0 || y.c1 || y.c0
+			var want bytes.Buffer
+			for _, coordinate := range []string{v.P.X, v.P.Y} {
+				c := strings.Split(coordinate, ",")
+				require.Len(t, c, 2)
+				want.Write(mustDecodeHex(t, strings.TrimPrefix(c[1], "0x")))
+				want.Write(mustDecodeHex(t, strings.TrimPrefix(c[0], "0x")))
+			}
+			assert.Equal(t, want.Bytes(), g2.ToBytes(p))
+		})
+	}
+}
+
+func toByteSlices(hs []hexBytes) [][]byte {
+	bs := make([][]byte, len(hs))
+	for i, h := range hs {
+		bs[i] = h
+	}
+	return bs
+}
+
+func toPubKeys(hs []hexBytes) []crypto.PubKey {
+	pubKeys := make([]crypto.PubKey, len(hs))
+	for i, h := range hs {
+		pubKeys[i] = PubKey(h)
+	}
+	return pubKeys
+}
@@ -0,0 +1,145 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+var errUnknownMember = errors.New("proTxHash is not a quorum member")
+
+// KeyShare is the share of a quorum member in the threshold key.
+type KeyShare struct {
+	ProTxHash crypto.ProTxHash
+	PrivKey   PrivKey
+	PubKey    PubKey
+}
+
+// QuorumKeyShares is the output of a trusted dealer: the key shares of every
+// quorum member along with the public data of the threshold key.
+type QuorumKeyShares struct {
+	// Threshold is the number of shares needed to recover a threshold signature.
+	Threshold int
+	// VerificationVector holds the public keys of the coefficients of the
+	// secret polynomial, the first one being the threshold public key.
+	VerificationVector VerificationVector
+	// ThresholdPublicKey is the public key of the quorum.
+	ThresholdPublicKey PubKey
+	// Shares are the key shares of the members, in the order they were given.
+	Shares []KeyShare
+}
+
+// GenerateQuorumKeyShares acts as a trusted dealer and splits a new random
+// threshold key between the members identified by proTxHashes, so that any
+// threshold of them can recover the threshold signature.
+//
+// The BLS id of every member is derived from its proTxHash with
+// BLSIDFromProTxHash.
+func GenerateQuorumKeyShares(threshold int, proTxHashes []crypto.ProTxHash) (*QuorumKeyShares, error) {
+	return generateQuorumKeyShares(rand.Reader, threshold, proTxHashes)
+}
+
+// generateQuorumKeyShares generates the polynomial of the threshold key with
+// the provided reader.
+func generateQuorumKeyShares(rand io.Reader, threshold int, proTxHashes []crypto.ProTxHash) (*QuorumKeyShares, error) {
+	if threshold < 1 || threshold > len(proTxHashes) {
+		return nil, fmt.Errorf("threshold %d is out of range [1, %d]", threshold, len(proTxHashes))
+	}
+	ids, err := blsIDScalars(proTxHashes)
+	if err != nil {
+		return nil, err
+	}
+	coefficients, vvec, err := randomPolynomial(rand, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]KeyShare, len(proTxHashes))
+	for i, id := range ids {
+		sk := scalarToBytes(evalPolynomial(coefficients, id))
+		pk, err := defaultBackend.publicKey(sk)
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = KeyShare{
+			ProTxHash: proTxHashes[i],
+			PrivKey:   sk,
+			PubKey:    pk,
+		}
+	}
+	return &QuorumKeyShares{
+		Threshold:          threshold,
+		VerificationVector: vvec,
+		ThresholdPublicKey: vvec[0],
+		Shares:             shares,
+	}, nil
+}
+
+// QuorumKeys returns the quorum keys of the member with the given proTxHash.
+func (q *QuorumKeyShares) QuorumKeys(proTxHash crypto.ProTxHash) (crypto.QuorumKeys, error) {
+	for _, share := range q.Shares {
+		if share.ProTxHash.Equal(proTxHash) {
+			return crypto.QuorumKeys{
+				PrivKey:            share.PrivKey,
+				PubKey:             share.PubKey,
+				ThresholdPublicKey: q.ThresholdPublicKey,
+			}, nil
+		}
+	}
+	return crypto.QuorumKeys{}, fmt.Errorf("%X: %w", proTxHash, errUnknownMember)
+}
+
+// blsIDScalars validates the proTxHashes of quorum members and returns the
+// scalars of their BLS ids.
+func blsIDScalars(proTxHashes []crypto.ProTxHash) ([]*big.Int, error) {
+	ids := make([]*big.Int, len(proTxHashes))
+	seen := make(map[string]struct{}, len(proTxHashes))
+	for i, proTxHash := range proTxHashes {
+		blsID, err := BLSIDFromProTxHash(proTxHash)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = blsID.scalar()
+		if ids[i].Sign() == 0 {
+			return nil, fmt.Errorf("proTxHash %X maps to the zero bls id", proTxHash)
+		}
+		if _, ok := seen[ids[i].String()]; ok {
+			return nil, fmt.Errorf("proTxHash %X: %w", proTxHash, errDuplicateBLSID)
+		}
+		seen[ids[i].String()] = struct{}{}
+	}
+	return ids, nil
+}
+
+// randomPolynomial generates the coefficients of a secret polynomial of degree
+// threshold-1 with the provided reader, along with its verification vector.
+func randomPolynomial(rand io.Reader, threshold int) ([]*big.Int, VerificationVector, error) {
+	coefficients := make([]*big.Int, threshold)
+	vvec := make(VerificationVector, threshold)
+	for i := range coefficients {
+		sk := genPrivKey(rand)
+		coefficients[i] = new(big.Int).SetBytes(sk)
+		pk, err := defaultBackend.publicKey(sk)
+		if err != nil {
+			return nil, nil, err
+		}
+		vvec[i] = pk
+	}
+	return coefficients, vvec, nil
+}
+
+// evalPolynomial evaluates the polynomial with the given coefficients, lowest
+// degree first, at x modulo the group order.
+func evalPolynomial(coefficients []*big.Int, x *big.Int) *big.Int {
+	y := new(big.Int)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y.Mul(y, x)
+		y.Add(y, coefficients[i])
+		y.Mod(y, curveOrder)
+	}
+	return y
+}
@@ -0,0 +1,94 @@
+package bls12381
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+func TestGenerateQuorumKeyShares(t *testing.T) {
+	testCases := []struct {
+		n         int
+		threshold int
+	}{
+		{n: 1, threshold: 1},
+		{n: 4, threshold: 3},
+		{n: 10, threshold: 6},
+	}
+	msg := crypto.CRandBytes(32)
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("%d of %d", tc.threshold, tc.n), func(t *testing.T) {
+			proTxHashes := crypto.RandProTxHashes(tc.n)
+			keys, err := GenerateQuorumKeyShares(tc.threshold, proTxHashes)
+			require.NoError(t, err)
+			require.Len(t, keys.Shares, tc.n)
+			require.Len(t, keys.VerificationVector, tc.threshold)
+			assert.Equal(t, keys.VerificationVector[0], keys.ThresholdPublicKey)
+
+			blsIDs := make([]BLSID, tc.n)
+			pubKeys := make([]crypto.PubKey, tc.n)
+			sigShares := make([][]byte, tc.n)
+			for i, share := range keys.Shares {
+				blsIDs[i] = mustBLSID(t, share.ProTxHash)
+				assert.Equal(t, proTxHashes[i], share.ProTxHash)
+				assert.Equal(t, share.PrivKey.PubKey(), share.PubKey)
+				pubKeys[i] = share.PubKey
+				sigShares[i] = mustSign(t, share.PrivKey, msg)
+
+				quorumKeys, err := keys.QuorumKeys(share.ProTxHash)
+				require.NoError(t, err)
+				assert.Equal(t, share.PrivKey, quorumKeys.PrivKey)
+				assert.Equal(t, share.PubKey, quorumKeys.PubKey)
+				assert.Equal(t, keys.ThresholdPublicKey, quorumKeys.ThresholdPublicKey)
+			}
+
+			// any threshold of the members recovers the threshold key
+			for from := 0; from+tc.threshold <= tc.n; from++ {
+				to := from + tc.threshold
+				thresholdPublicKey, err := RecoverThresholdPublicKeyFromPublicKeys(pubKeys[from:to], blsIDs[from:to])
+				require.NoError(t, err)
+				assert.Equal(t, keys.ThresholdPublicKey, thresholdPublicKey)
+
+				sig, err := RecoverThresholdSignatureFromShares(sigShares[from:to], blsIDs[from:to])
+				require.NoError(t, err)
+				assert.True(t, keys.ThresholdPublicKey.VerifySignature(msg, sig))
+			}
+			// but fewer members do not
+			if tc.threshold > 2 {
+				sig, err := RecoverThresholdSignatureFromShares(sigShares[:tc.threshold-1], blsIDs[:tc.threshold-1])
+				require.NoError(t, err)
+				assert.False(t, keys.ThresholdPublicKey.VerifySignature(msg, sig))
+			}
+
+			_, err = keys.QuorumKeys(crypto.RandProTxHash())
+			assert.ErrorIs(t, err, errUnknownMember)
+		})
+	}
+}
+
+func TestGenerateQuorumKeySharesInvalidInput(t *testing.T) {
+	proTxHashes := crypto.RandProTxHashes(3)
+	testCases := []struct {
+		name        string
+		threshold   int
+		proTxHashes []crypto.ProTxHash
+	}{
+		{name: "zero threshold", threshold: 0, proTxHashes: proTxHashes},
+		{name: "threshold above size", threshold: 4, proTxHashes: proTxHashes},
+		{name: "short proTxHash", threshold: 2, proTxHashes: append(proTxHashes[:2:2], proTxHashes[2][1:])},
+		{name: "duplicate proTxHash", threshold: 2, proTxHashes: append(proTxHashes[:2:2], proTxHashes[0])},
+		{name: "zero proTxHash", threshold: 2, proTxHashes: append(proTxHashes[:2:2], make([]byte, crypto.ProTxHashSize))},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := GenerateQuorumKeyShares(tc.threshold, tc.proTxHashes)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package bls12381
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// DerivationPathPurpose is the purpose, the first index of EIP-2334
+	// derivation paths, which identifies BLS12-381 keys.
+	DerivationPathPurpose = 12381
+
+	// lamportChunks is the number of 32 byte chunks of EIP-2333 Lamport keys.
+	lamportChunks = 255
+)
+
+var errInvalidDerivationPath = errors.New("invalid derivation path")
+
+// DeriveMasterSK derives the master private key of the EIP-2333 key tree of
+// seed, which must be at least SeedSize bytes.
+func DeriveMasterSK(seed []byte) (PrivKey, error) {
+	if len(seed) < SeedSize {
+		return nil, errSeedTooShort
+	}
+	return hkdfModR(seed)
+}
+
+// DeriveChildSK derives the child private key with the given index of parent,
+// as specified by EIP-2333.
+func DeriveChildSK(parent PrivKey, index uint32) (PrivKey, error) {
+	if len(parent) != PrivateKeySize {
+		return nil, errInvalidPrivateKeySize(len(parent))
+	}
+	lamportPK, err := parentSKToLamportPK(parent, index)
+	if err != nil {
+		return nil, err
+	}
+	return hkdfModR(lamportPK)
+}
+
+// DeriveSKFromPath derives the private key at an EIP-2334 path, such as
+// "m/12381/3600/0/0", from the master key of seed. Keys for different roles,
+// such as operator, voting and test keys, can thus be derived from one seed by
+// giving each role its own account or use index.
+func DeriveSKFromPath(seed []byte, path string) (PrivKey, error) {
+	indices, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	sk, err := DeriveMasterSK(seed)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indices {
+		child, err := DeriveChildSK(sk, index)
+		sk.Wipe()
+		if err != nil {
+			return nil, err
+		}
+		sk = child
+	}
+	return sk, nil
+}
+
+// ParseDerivationPath parses an EIP-2334 path into the indices of the
+// children to derive from the master key. The path starts with "m" and its
+// first index, if any, must be DerivationPathPurpose.
+func ParseDerivationPath(path string) ([]uint32, error) {
+	nodes := strings.Split(path, "/")
+	if nodes[0] != "m" {
+		return nil, fmt.Errorf("%w %q: must start with m", errInvalidDerivationPath, path)
+	}
+	indices := make([]uint32, 0, len(nodes)-1)
+	for _, node := range nodes[1:] {
+		index, err := strconv.ParseUint(node, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: index %q: %v", errInvalidDerivationPath, path, node, err)
+		}
+		indices = append(indices, uint32(index))
+	}
+	if len(indices) > 0 && indices[0] != DerivationPathPurpose {
+		return nil, fmt.Errorf("%w %q: purpose must be %d", errInvalidDerivationPath, path, DerivationPathPurpose)
+	}
+	return indices, nil
+}
+
+// hkdfModR derives a nonzero private key from ikm, as HKDF_mod_r of EIP-2333
+// and KeyGen of the IETF BLS signature draft, version 4.
+func hkdfModR(ikm []byte) (PrivKey, error) {
+	salt := []byte(keyGenSalt)
+	input := make([]byte, len(ikm)+1)
+	copy(input, ikm)
+	defer wipeBytes(input)
+	okm := make([]byte, keyGenOKMSize)
+	defer wipeBytes(okm)
+	sk := new(big.Int)
+	defer wipeScalar(sk)
+	for sk.Sign() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		kdf := hkdf.New(sha256.New, input, salt, []byte{0, keyGenOKMSize})
+		if _, err := io.ReadFull(kdf, okm); err != nil {
+			return nil, err
+		}
+		sk.SetBytes(okm)
+		sk.Mod(sk, curveOrder)
+	}
+	return scalarToBytes(sk), nil
+}
+
+// parentSKToLamportPK returns the compressed Lamport public key EIP-2333
+// derives the child with the given index of parent from.
+func parentSKToLamportPK(parent PrivKey, index uint32) ([]byte, error) {
+	salt := make([]byte, 4)
+	binary.BigEndian.PutUint32(salt, index)
+	ikm := append([]byte(nil), parent...)
+	defer wipeBytes(ikm)
+
+	lamportPK := sha256.New()
+	for i := 0; i < 2; i++ {
+		if i == 1 {
+			for j := range ikm {
+				ikm[j] = ^ikm[j]
+			}
+		}
+		lamportSK, err := ikmToLamportSK(ikm, salt)
+		if err != nil {
+			return nil, err
+		}
+		for j := 0; j < lamportChunks; j++ {
+			chunk := sha256.Sum256(lamportSK[j*sha256.Size : (j+1)*sha256.Size])
+			lamportPK.Write(chunk[:])
+		}
+		wipeBytes(lamportSK)
+	}
+	return lamportPK.Sum(nil), nil
+}
+
+// ikmToLamportSK returns the chunks of the Lamport private key EIP-2333 derives
+// from ikm and salt, concatenated.
+func ikmToLamportSK(ikm, salt []byte) ([]byte, error) {
+	okm := make([]byte, lamportChunks*sha256.Size)
+	kdf := hkdf.New(sha256.New, ikm, salt, nil)
+	if _, err := io.ReadFull(kdf, okm); err != nil {
+		wipeBytes(okm)
+		return nil, err
+	}
+	return okm, nil
+}
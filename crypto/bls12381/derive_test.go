@@ -0,0 +1,111 @@
+package bls12381
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDeriveKnownAnswers checks key derivation against the test cases of
+// EIP-2333.
+func TestDeriveKnownAnswers(t *testing.T) {
+	testCases := []struct {
+		seed     string
+		masterSK string
+		index    uint32
+		childSK  string
+	}{
+		{
+			seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+			masterSK: "6083874454709270928345386274498605044986640685124978867557563392430687146096",
+			index:    0,
+			childSK:  "20397789859736650942317412262472558107875392172444076792671091975210932703118",
+		},
+		{
+			seed:     "3141592653589793238462643383279502884197169399375105820974944592",
+			masterSK: "29757020647961307431480504535336562678282505419141012933316116377660817309383",
+			index:    3141592653,
+			childSK:  "25457201688850691947727629385191704516744796114925897962676248250929345014287",
+		},
+		{
+			seed:     "0099ff991111002299dd7744ee3355bbdd8844115566cc55663355668888cc00",
+			masterSK: "27580842291869792442942448775674722299803720648445448686099262467207037398656",
+			index:    4294967295,
+			childSK:  "29358610794459428860402234341874281240803786294062035874021252734817515685787",
+		},
+		{
+			seed:     "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+			masterSK: "19022158461524446591288038168518313374041767046816487870552872741050760015818",
+			index:    42,
+			childSK:  "31372231650479070279774297061823572166496564838472787488249775572789064611981",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.masterSK, func(t *testing.T) {
+			masterSK, err := DeriveMasterSK(mustDecodeHex(t, tc.seed))
+			require.NoError(t, err)
+			assert.Equal(t, tc.masterSK, new(big.Int).SetBytes(masterSK).String())
+
+			childSK, err := DeriveChildSK(masterSK, tc.index)
+			require.NoError(t, err)
+			assert.Equal(t, tc.childSK, new(big.Int).SetBytes(childSK).String())
+		})
+	}
+}
+
+func TestDeriveSKFromPath(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x42}, SeedSize)
+	masterSK, err := DeriveMasterSK(seed)
+	require.NoError(t, err)
+
+	sk, err := DeriveSKFromPath(seed, "m")
+	require.NoError(t, err)
+	assert.Equal(t, masterSK, sk)
+
+	want := masterSK
+	for _, index := range []uint32{DerivationPathPurpose, 3600, 7, 0} {
+		want, err = DeriveChildSK(want, index)
+		require.NoError(t, err)
+	}
+	sk, err = DeriveSKFromPath(seed, "m/12381/3600/7/0")
+	require.NoError(t, err)
+	assert.Equal(t, want, sk)
+	_, err = ParsePrivKey(sk)
+	assert.NoError(t, err)
+
+	other, err := DeriveSKFromPath(seed, "m/12381/3600/7/1")
+	require.NoError(t, err)
+	assert.NotEqual(t, sk, other)
+
+	_, err = DeriveMasterSK(seed[1:])
+	assert.ErrorIs(t, err, errSeedTooShort)
+	_, err = DeriveChildSK(masterSK[1:], 0)
+	assert.ErrorIs(t, err, ErrPrivKeyInvalidSize)
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	indices, err := ParseDerivationPath("m/12381/3600/0/0/0")
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{12381, 3600, 0, 0, 0}, indices)
+	indices, err = ParseDerivationPath("m")
+	require.NoError(t, err)
+	assert.Empty(t, indices)
+
+	for _, path := range []string{
+		"",
+		"/12381/3600",
+		"n/12381/3600",
+		"m/12381/3600/",
+		"m/12381/-1",
+		"m/12381/4294967296",
+		"m/12381/3600'/0",
+		"m/44/5/0",
+	} {
+		_, err := ParseDerivationPath(path)
+		assert.ErrorIs(t, err, errInvalidDerivationPath, path)
+	}
+}
@@ -0,0 +1,492 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+// The DKG of a quorum follows the Joint-Feldman protocol. Every member deals
+// shares of its own secret polynomial to the others and the threshold key is
+// the sum of the polynomials of all qualified members:
+//
+//  1. Contribute: every member broadcasts a DKGContribution with the
+//     verification vector of its polynomial and sends each other member its
+//     DKGSecretShare over a private channel.
+//  2. Complain: every member checks the shares it received against the
+//     verification vectors and broadcasts a DKGComplaint against each
+//     contributor whose share is missing or invalid.
+//  3. Justify: every member answers each complaint against it by broadcasting
+//     a DKGJustification that reveals the disputed share.
+//  4. Finalize: contributors with a complaint that was not answered by a valid
+//     justification are disqualified, and the key shares of the qualified
+//     contributors are added up into the quorum keys.
+//
+// DKGMember only implements the protocol logic: the caller moves it from one
+// phase to the next and delivers the messages it produces to the other
+// members. The transport must authenticate senders, keep secret shares
+// private and make broadcasts reliable, so that all honest members see the
+// same contributions, complaints and justifications.
+
+var (
+	errDKGPhase      = errors.New("message does not belong to the current dkg phase")
+	errDKGOwnMessage = errors.New("message from self")
+)
+
+type dkgPhase int
+
+const (
+	dkgPhaseContribute dkgPhase = iota
+	dkgPhaseComplain
+	dkgPhaseJustify
+	dkgPhaseFinalized
+)
+
+// DKGMessage is a message exchanged by the members of a DKG session.
+type DKGMessage interface {
+	// Sender returns the proTxHash of the member that sent the message.
+	Sender() crypto.ProTxHash
+	// Recipient returns the proTxHash of the only member the message must be
+	// delivered to, or nil if it is broadcast to all the other members.
+	Recipient() crypto.ProTxHash
+	// ValidateBasic performs stateless validation of the message.
+	ValidateBasic() error
+}
+
+// DKGContribution publishes the verification vector of the secret polynomial
+// of a member.
+type DKGContribution struct {
+	From               crypto.ProTxHash
+	VerificationVector VerificationVector
+}
+
+// DKGSecretShare carries the share of the secret polynomial of a member for
+// another member. It must be sent over a private channel.
+type DKGSecretShare struct {
+	From  crypto.ProTxHash
+	To    crypto.ProTxHash
+	Share PrivKey
+}
+
+// DKGComplaint accuses a member of not having sent a valid secret share to the
+// sender.
+type DKGComplaint struct {
+	From    crypto.ProTxHash
+	Against crypto.ProTxHash
+}
+
+// DKGJustification answers a complaint by publicly revealing the secret share
+// that the complaining member should have received.
+type DKGJustification struct {
+	From  crypto.ProTxHash
+	To    crypto.ProTxHash
+	Share PrivKey
+}
+
+// Sender implements DKGMessage.
+func (m *DKGContribution) Sender() crypto.ProTxHash { return m.From }
+
+// Recipient implements DKGMessage.
+func (m *DKGContribution) Recipient() crypto.ProTxHash { return nil }
+
+// ValidateBasic implements DKGMessage.
+func (m *DKGContribution) ValidateBasic() error {
+	if err := crypto.ProTxHashValidate(m.From); err != nil {
+		return err
+	}
+	return m.VerificationVector.Validate()
+}
+
+// Sender implements DKGMessage.
+func (m *DKGSecretShare) Sender() crypto.ProTxHash { return m.From }
+
+// Recipient implements DKGMessage.
+func (m *DKGSecretShare) Recipient() crypto.ProTxHash { return m.To }
+
+// ValidateBasic implements DKGMessage.
+func (m *DKGSecretShare) ValidateBasic() error {
+	return validateDKGShare(m.From, m.To, m.Share)
+}
+
+// Sender implements DKGMessage.
+func (m *DKGComplaint) Sender() crypto.ProTxHash { return m.From }
+
+// Recipient implements DKGMessage.
+func (m *DKGComplaint) Recipient() crypto.ProTxHash { return nil }
+
+// ValidateBasic implements DKGMessage.
+func (m *DKGComplaint) ValidateBasic() error {
+	if err := crypto.ProTxHashValidate(m.From); err != nil {
+		return err
+	}
+	return crypto.ProTxHashValidate(m.Against)
+}
+
+// Sender implements DKGMessage.
+func (m *DKGJustification) Sender() crypto.ProTxHash { return m.From }
+
+// Recipient implements DKGMessage.
+func (m *DKGJustification) Recipient() crypto.ProTxHash { return nil }
+
+// ValidateBasic implements DKGMessage.
+func (m *DKGJustification) ValidateBasic() error {
+	return validateDKGShare(m.From, m.To, m.Share)
+}
+
+func validateDKGShare(from, to crypto.ProTxHash, share PrivKey) error {
+	if err := crypto.ProTxHashValidate(from); err != nil {
+		return err
+	}
+	if err := crypto.ProTxHashValidate(to); err != nil {
+		return err
+	}
+	if len(share) != PrivateKeySize {
+		return errInvalidPrivateKeySize(len(share))
+	}
+	return nil
+}
+
+// DKGResult is the outcome of a DKG session for one member.
+type DKGResult struct {
+	// QuorumKeys are the key share of the member and the threshold public key.
+	QuorumKeys crypto.QuorumKeys
+	// VerificationVector is the verification vector of the threshold key.
+	VerificationVector VerificationVector
+	// Qualified lists the members whose contributions make up the threshold
+	// key, in the order of the quorum.
+	Qualified []crypto.ProTxHash
+	// Disqualified lists the members that did not contribute or were caught
+	// misbehaving, in the order of the quorum.
+	Disqualified []crypto.ProTxHash
+}
+
+// DKGMember runs the DKG protocol on behalf of one quorum member.
+// It is not safe for concurrent use.
+type DKGMember struct {
+	threshold int
+	self      crypto.ProTxHash
+	members   []crypto.ProTxHash
+	ids       map[string]*big.Int
+	rand      io.Reader
+	phase     dkgPhase
+
+	// coefficients of the secret polynomial of this member
+	coefficients []*big.Int
+	// contributions holds the verification vectors by contributor
+	contributions map[string]VerificationVector
+	// shares holds the secret shares received by contributor
+	shares map[string]PrivKey
+	// complaints holds the set of complaining members by accused contributor
+	complaints map[string]map[string]struct{}
+	// justified holds the valid justified shares by contributor and complainer
+	justified map[string]map[string]PrivKey
+	// disqualified contributors
+	disqualified map[string]struct{}
+}
+
+// NewDKGMember creates the DKG state of member self of the quorum made of
+// members, for a threshold key needing threshold signature shares.
+func NewDKGMember(threshold int, members []crypto.ProTxHash, self crypto.ProTxHash) (*DKGMember, error) {
+	return newDKGMember(rand.Reader, threshold, members, self)
+}
+
+func newDKGMember(rand io.Reader, threshold int, members []crypto.ProTxHash, self crypto.ProTxHash) (*DKGMember, error) {
+	if threshold < 1 || threshold > len(members) {
+		return nil, fmt.Errorf("threshold %d is out of range [1, %d]", threshold, len(members))
+	}
+	scalars, err := blsIDScalars(members)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]*big.Int, len(members))
+	for i, member := range members {
+		ids[string(member)] = scalars[i]
+	}
+	if _, ok := ids[string(self)]; !ok {
+		return nil, fmt.Errorf("%X: %w", self, errUnknownMember)
+	}
+	return &DKGMember{
+		threshold:     threshold,
+		self:          self,
+		members:       members,
+		ids:           ids,
+		rand:          rand,
+		contributions: make(map[string]VerificationVector),
+		shares:        make(map[string]PrivKey),
+		complaints:    make(map[string]map[string]struct{}),
+		justified:     make(map[string]map[string]PrivKey),
+		disqualified:  make(map[string]struct{}),
+	}, nil
+}
+
+// Contribute generates the secret polynomial of the member. It returns the
+// contribution to broadcast and the secret shares to send to the other
+// members.
+func (m *DKGMember) Contribute() ([]DKGMessage, error) {
+	if m.phase != dkgPhaseContribute || m.coefficients != nil {
+		return nil, errors.New("contribution already made")
+	}
+	coefficients, vvec, err := randomPolynomial(m.rand, m.threshold)
+	if err != nil {
+		return nil, err
+	}
+	m.coefficients = coefficients
+	m.contributions[string(m.self)] = vvec
+
+	msgs := []DKGMessage{&DKGContribution{From: m.self, VerificationVector: vvec}}
+	for _, member := range m.members {
+		share := m.shareFor(member)
+		if member.Equal(m.self) {
+			m.shares[string(m.self)] = share
+			continue
+		}
+		msgs = append(msgs, &DKGSecretShare{From: m.self, To: member, Share: share})
+	}
+	return msgs, nil
+}
+
+// HandleMessage processes a message received from another member.
+// Contributions and secret shares are accepted until Complain is called,
+// complaints until Justify is called and justifications until Finalize is
+// called.
+func (m *DKGMember) HandleMessage(msg DKGMessage) error {
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+	from := msg.Sender()
+	if _, ok := m.ids[string(from)]; !ok {
+		return fmt.Errorf("sender %X: %w", from, errUnknownMember)
+	}
+	if from.Equal(m.self) {
+		return errDKGOwnMessage
+	}
+	switch msg := msg.(type) {
+	case *DKGContribution:
+		return m.handleContribution(msg)
+	case *DKGSecretShare:
+		return m.handleSecretShare(msg)
+	case *DKGComplaint:
+		return m.handleComplaint(msg)
+	case *DKGJustification:
+		return m.handleJustification(msg)
+	default:
+		return fmt.Errorf("unknown dkg message %T", msg)
+	}
+}
+
+func (m *DKGMember) handleContribution(msg *DKGContribution) error {
+	if m.phase != dkgPhaseContribute {
+		return errDKGPhase
+	}
+	if len(msg.VerificationVector) != m.threshold {
+		return fmt.Errorf("verification vector of %X has %d items, expected %d",
+			msg.From, len(msg.VerificationVector), m.threshold)
+	}
+	if _, ok := m.contributions[string(msg.From)]; ok {
+		return fmt.Errorf("duplicate contribution from %X", msg.From)
+	}
+	m.contributions[string(msg.From)] = msg.VerificationVector
+	return nil
+}
+
+func (m *DKGMember) handleSecretShare(msg *DKGSecretShare) error {
+	if m.phase != dkgPhaseContribute {
+		return errDKGPhase
+	}
+	if !msg.To.Equal(m.self) {
+		return fmt.Errorf("secret share for %X delivered to %X", msg.To, m.self)
+	}
+	if _, ok := m.shares[string(msg.From)]; ok {
+		return fmt.Errorf("duplicate secret share from %X", msg.From)
+	}
+	m.shares[string(msg.From)] = msg.Share
+	return nil
+}
+
+func (m *DKGMember) handleComplaint(msg *DKGComplaint) error {
+	if m.phase != dkgPhaseComplain {
+		return errDKGPhase
+	}
+	if _, ok := m.ids[string(msg.Against)]; !ok {
+		return fmt.Errorf("complaint against %X: %w", msg.Against, errUnknownMember)
+	}
+	m.addComplaint(msg.Against, msg.From)
+	return nil
+}
+
+func (m *DKGMember) handleJustification(msg *DKGJustification) error {
+	if m.phase != dkgPhaseJustify {
+		return errDKGPhase
+	}
+	if _, ok := m.complaints[string(msg.From)][string(msg.To)]; !ok {
+		return fmt.Errorf("justification of %X without a complaint from %X", msg.From, msg.To)
+	}
+	if _, ok := m.justified[string(msg.From)][string(msg.To)]; ok {
+		return nil
+	}
+	if !m.verifyShare(msg.From, msg.To, msg.Share) {
+		m.disqualified[string(msg.From)] = struct{}{}
+		return nil
+	}
+	if m.justified[string(msg.From)] == nil {
+		m.justified[string(msg.From)] = make(map[string]PrivKey)
+	}
+	m.justified[string(msg.From)][string(msg.To)] = msg.Share
+	return nil
+}
+
+// Complain ends the contribution phase. It checks the secret shares received
+// against the contributions and returns the complaints to broadcast.
+func (m *DKGMember) Complain() ([]DKGMessage, error) {
+	if m.phase != dkgPhaseContribute || m.coefficients == nil {
+		return nil, errors.New("complaints must follow the contribution")
+	}
+	m.phase = dkgPhaseComplain
+
+	var msgs []DKGMessage
+	for _, member := range m.members {
+		if member.Equal(m.self) {
+			continue
+		}
+		if _, ok := m.contributions[string(member)]; !ok {
+			// without a contribution the member is not qualified anyway
+			continue
+		}
+		if share, ok := m.shares[string(member)]; ok && m.verifyShare(member, m.self, share) {
+			continue
+		}
+		m.addComplaint(member, m.self)
+		msgs = append(msgs, &DKGComplaint{From: m.self, Against: member})
+	}
+	return msgs, nil
+}
+
+// Justify ends the complaint phase and returns the justifications to broadcast
+// in answer to the complaints against the member.
+func (m *DKGMember) Justify() ([]DKGMessage, error) {
+	if m.phase != dkgPhaseComplain {
+		return nil, errors.New("justifications must follow the complaints")
+	}
+	m.phase = dkgPhaseJustify
+
+	var msgs []DKGMessage
+	for _, member := range m.members {
+		if _, ok := m.complaints[string(m.self)][string(member)]; !ok {
+			continue
+		}
+		justification := &DKGJustification{From: m.self, To: member, Share: m.shareFor(member)}
+		if m.justified[string(m.self)] == nil {
+			m.justified[string(m.self)] = make(map[string]PrivKey)
+		}
+		m.justified[string(m.self)][string(member)] = justification.Share
+		msgs = append(msgs, justification)
+	}
+	return msgs, nil
+}
+
+// Finalize ends the DKG session and computes the quorum keys of the member
+// from the contributions of the qualified members.
+func (m *DKGMember) Finalize() (*DKGResult, error) {
+	if m.phase != dkgPhaseJustify {
+		return nil, errors.New("finalization must follow the justifications")
+	}
+	m.phase = dkgPhaseFinalized
+	defer func() { m.coefficients = nil }()
+
+	for contributor, complainers := range m.complaints {
+		for complainer := range complainers {
+			if _, ok := m.justified[contributor][complainer]; !ok {
+				m.disqualified[contributor] = struct{}{}
+			}
+		}
+	}
+
+	result := &DKGResult{}
+	var qualifiedVVecs []VerificationVector
+	sk := new(big.Int)
+	for _, member := range m.members {
+		vvec, ok := m.contributions[string(member)]
+		if _, disqualified := m.disqualified[string(member)]; !ok || disqualified {
+			result.Disqualified = append(result.Disqualified, member)
+			continue
+		}
+		result.Qualified = append(result.Qualified, member)
+		qualifiedVVecs = append(qualifiedVVecs, vvec)
+
+		share, ok := m.justified[string(member)][string(m.self)]
+		if !ok {
+			share = m.shares[string(member)]
+		}
+		sk.Add(sk, new(big.Int).SetBytes(share))
+	}
+	if len(result.Qualified) < m.threshold {
+		return nil, fmt.Errorf("only %d qualified contributions, %d needed", len(result.Qualified), m.threshold)
+	}
+
+	result.VerificationVector = make(VerificationVector, m.threshold)
+	for i := range result.VerificationVector {
+		coefficients := make([][]byte, len(qualifiedVVecs))
+		for j, vvec := range qualifiedVVecs {
+			coefficients[j] = vvec[i]
+		}
+		pk, err := defaultBackend.aggregatePubKeys(coefficients)
+		if err != nil {
+			return nil, err
+		}
+		result.VerificationVector[i] = pk
+	}
+
+	privKey := PrivKey(scalarToBytes(sk.Mod(sk, curveOrder)))
+	pubKey, err := defaultBackend.publicKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := result.VerificationVector.eval(m.ids[string(m.self)])
+	if err != nil {
+		return nil, err
+	}
+	if !PubKey(pubKey).Equals(expected) {
+		return nil, errors.New("key share does not match the verification vector")
+	}
+	result.QuorumKeys = crypto.QuorumKeys{
+		PrivKey:            privKey,
+		PubKey:             PubKey(pubKey),
+		ThresholdPublicKey: result.VerificationVector[0],
+	}
+	return result, nil
+}
+
+// shareFor evaluates the secret polynomial of the member at the BLS id of
+// member.
+func (m *DKGMember) shareFor(member crypto.ProTxHash) PrivKey {
+	return scalarToBytes(evalPolynomial(m.coefficients, m.ids[string(member)]))
+}
+
+// verifyShare reports whether share is the share of the polynomial of
+// contributor for member, according to the contribution of contributor.
+func (m *DKGMember) verifyShare(contributor, member crypto.ProTxHash, share PrivKey) bool {
+	vvec, ok := m.contributions[string(contributor)]
+	if !ok {
+		return false
+	}
+	expected, err := vvec.eval(m.ids[string(member)])
+	if err != nil {
+		return false
+	}
+	pk, err := defaultBackend.publicKey(share)
+	if err != nil {
+		return false
+	}
+	return expected.Equals(PubKey(pk))
+}
+
+func (m *DKGMember) addComplaint(against, from crypto.ProTxHash) {
+	if m.complaints[string(against)] == nil {
+		m.complaints[string(against)] = make(map[string]struct{})
+	}
+	m.complaints[string(against)][string(from)] = struct{}{}
+}
@@ -0,0 +1,234 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+// dkgNetwork is an in-process network that runs a DKG session between its
+// members, delivering every message as soon as it is produced.
+type dkgNetwork struct {
+	proTxHashes []crypto.ProTxHash
+	members     []*DKGMember
+	// offline members neither send nor receive messages
+	offline map[int]bool
+	// tamper, when set, is applied to every message before it is delivered;
+	// returning nil drops the message
+	tamper func(msg DKGMessage) DKGMessage
+	// beforeComplain, when set, is called before the members check the
+	// secret shares they received
+	beforeComplain func()
+}
+
+func newDKGNetwork(t *testing.T, threshold, n int) *dkgNetwork {
+	t.Helper()
+	network := &dkgNetwork{
+		proTxHashes: crypto.RandProTxHashes(n),
+		members:     make([]*DKGMember, n),
+		offline:     make(map[int]bool),
+	}
+	for i, proTxHash := range network.proTxHashes {
+		member, err := NewDKGMember(threshold, network.proTxHashes, proTxHash)
+		require.NoError(t, err)
+		network.members[i] = member
+	}
+	return network
+}
+
+// run executes all the phases of the DKG and returns the results of the
+// members that are online.
+func (n *dkgNetwork) run(t *testing.T) map[int]*DKGResult {
+	t.Helper()
+	n.phase(t, (*DKGMember).Contribute)
+	if n.beforeComplain != nil {
+		n.beforeComplain()
+	}
+	n.phase(t, (*DKGMember).Complain)
+	n.phase(t, (*DKGMember).Justify)
+	results := make(map[int]*DKGResult)
+	for i, member := range n.members {
+		if n.offline[i] {
+			continue
+		}
+		result, err := member.Finalize()
+		require.NoError(t, err)
+		results[i] = result
+	}
+	return results
+}
+
+func (n *dkgNetwork) phase(t *testing.T, step func(*DKGMember) ([]DKGMessage, error)) {
+	t.Helper()
+	var msgs []DKGMessage
+	for i, member := range n.members {
+		if n.offline[i] {
+			continue
+		}
+		out, err := step(member)
+		require.NoError(t, err)
+		msgs = append(msgs, out...)
+	}
+	for _, msg := range msgs {
+		if n.tamper != nil {
+			if msg = n.tamper(msg); msg == nil {
+				continue
+			}
+		}
+		for i, member := range n.members {
+			if n.offline[i] || member.self.Equal(msg.Sender()) {
+				continue
+			}
+			if recipient := msg.Recipient(); recipient != nil && !recipient.Equal(member.self) {
+				continue
+			}
+			require.NoError(t, member.HandleMessage(msg))
+		}
+	}
+}
+
+func TestDKGHonestMembers(t *testing.T) {
+	const threshold, n = 3, 5
+	network := newDKGNetwork(t, threshold, n)
+	results := network.run(t)
+	require.Len(t, results, n)
+
+	msg := crypto.CRandBytes(32)
+	thresholdPublicKey := results[0].QuorumKeys.ThresholdPublicKey
+	blsIDs := make([]BLSID, n)
+	pubKeys := make([]crypto.PubKey, n)
+	sigShares := make([][]byte, n)
+	for i, result := range results {
+		assert.Equal(t, thresholdPublicKey, result.QuorumKeys.ThresholdPublicKey)
+		assert.Equal(t, results[0].VerificationVector, result.VerificationVector)
+		assert.Equal(t, network.proTxHashes, result.Qualified)
+		assert.Empty(t, result.Disqualified)
+		assert.Equal(t, result.QuorumKeys.PrivKey.PubKey(), result.QuorumKeys.PubKey)
+
+		blsIDs[i] = mustBLSID(t, network.proTxHashes[i])
+		pubKeys[i] = result.QuorumKeys.PubKey
+		sig, err := result.QuorumKeys.PrivKey.Sign(msg)
+		require.NoError(t, err)
+		sigShares[i] = sig
+	}
+
+	for from := 0; from+threshold <= n; from++ {
+		to := from + threshold
+		recovered, err := RecoverThresholdPublicKeyFromPublicKeys(pubKeys[from:to], blsIDs[from:to])
+		require.NoError(t, err)
+		assert.Equal(t, thresholdPublicKey, recovered)
+
+		sig, err := RecoverThresholdSignatureFromShares(sigShares[from:to], blsIDs[from:to])
+		require.NoError(t, err)
+		assert.True(t, thresholdPublicKey.VerifySignature(msg, sig))
+	}
+}
+
+func TestDKGMaliciousMembers(t *testing.T) {
+	const threshold, n = 4, 7
+	network := newDKGNetwork(t, threshold, n)
+	p := network.proTxHashes
+	_, forgedVVec, err := randomPolynomial(rand.Reader, threshold)
+	require.NoError(t, err)
+
+	network.offline[6] = true
+	network.tamper = func(msg DKGMessage) DKGMessage {
+		switch msg := msg.(type) {
+		case *DKGSecretShare:
+			// 1 and 2 send an invalid share to the next member, but only
+			// 2 answers the complaint
+			if msg.From.Equal(p[1]) && msg.To.Equal(p[2]) || msg.From.Equal(p[2]) && msg.To.Equal(p[3]) {
+				return &DKGSecretShare{From: msg.From, To: msg.To, Share: GenPrivKey()}
+			}
+		case *DKGJustification:
+			if msg.From.Equal(p[1]) {
+				return nil
+			}
+		case *DKGContribution:
+			// 5 publishes a verification vector that does not match its shares
+			if msg.From.Equal(p[5]) {
+				return &DKGContribution{From: msg.From, VerificationVector: forgedVVec}
+			}
+		}
+		return msg
+	}
+	network.beforeComplain = func() {
+		// 3 makes up a complaint against 4, which 4 answers
+		delete(network.members[3].shares, string(p[4]))
+	}
+	results := network.run(t)
+
+	honest := []int{0, 2, 3, 4}
+	msg := crypto.CRandBytes(32)
+	thresholdPublicKey := results[0].QuorumKeys.ThresholdPublicKey
+	blsIDs := make([]BLSID, len(honest))
+	sigShares := make([][]byte, len(honest))
+	for i, member := range honest {
+		result := results[member]
+		assert.Equal(t, []crypto.ProTxHash{p[0], p[2], p[3], p[4]}, result.Qualified, "member %d", member)
+		assert.Equal(t, []crypto.ProTxHash{p[1], p[5], p[6]}, result.Disqualified, "member %d", member)
+		assert.Equal(t, results[0].VerificationVector, result.VerificationVector)
+		assert.Equal(t, thresholdPublicKey, result.QuorumKeys.ThresholdPublicKey)
+
+		blsIDs[i] = mustBLSID(t, p[member])
+		sig, err := result.QuorumKeys.PrivKey.Sign(msg)
+		require.NoError(t, err)
+		sigShares[i] = sig
+	}
+	sig, err := RecoverThresholdSignatureFromShares(sigShares, blsIDs)
+	require.NoError(t, err)
+	assert.True(t, thresholdPublicKey.VerifySignature(msg, sig))
+}
+
+func TestDKGNotEnoughQualifiedMembers(t *testing.T) {
+	const threshold, n = 3, 4
+	network := newDKGNetwork(t, threshold, n)
+	network.offline[2] = true
+	network.offline[3] = true
+	network.phase(t, (*DKGMember).Contribute)
+	network.phase(t, (*DKGMember).Complain)
+	network.phase(t, (*DKGMember).Justify)
+	_, err := network.members[0].Finalize()
+	assert.Error(t, err)
+}
+
+func TestDKGHandleMessage(t *testing.T) {
+	const threshold, n = 2, 3
+	network := newDKGNetwork(t, threshold, n)
+	p := network.proTxHashes
+	member := network.members[0]
+
+	out, err := network.members[1].Contribute()
+	require.NoError(t, err)
+	contribution := out[0].(*DKGContribution)
+	require.NoError(t, member.HandleMessage(contribution))
+	assert.Error(t, member.HandleMessage(contribution), "duplicate contribution")
+
+	testCases := []struct {
+		name string
+		msg  DKGMessage
+	}{
+		{name: "own message", msg: &DKGComplaint{From: p[0], Against: p[1]}},
+		{name: "unknown sender", msg: &DKGContribution{From: crypto.RandProTxHash(), VerificationVector: contribution.VerificationVector}},
+		{name: "short verification vector", msg: &DKGContribution{From: p[2], VerificationVector: contribution.VerificationVector[:1]}},
+		{name: "share for another member", msg: &DKGSecretShare{From: p[2], To: p[1], Share: GenPrivKey()}},
+		{name: "invalid share size", msg: &DKGSecretShare{From: p[2], To: p[0], Share: GenPrivKey()[1:]}},
+		{name: "complaint too early", msg: &DKGComplaint{From: p[2], Against: p[1]}},
+		{name: "justification too early", msg: &DKGJustification{From: p[2], To: p[1], Share: GenPrivKey()}},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Error(t, member.HandleMessage(tc.msg))
+		})
+	}
+
+	_, err = member.Complain()
+	assert.Error(t, err, "complaints before contributing")
+	_, err = member.Finalize()
+	assert.Error(t, err, "finalization before justifications")
+}
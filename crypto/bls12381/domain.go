@@ -0,0 +1,115 @@
+package bls12381
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+// maxDSTSize is the largest domain separation tag RFC 9380 allows.
+const maxDSTSize = 255
+
+var errInvalidDST = errors.New("invalid domain separation tag")
+
+// DomainScheme is a signature scheme that hashes messages to G2 with an
+// application-specific domain separation tag instead of the one of its
+// ciphersuite. Signatures produced under one tag never verify under another,
+// so giving every purpose its own tag, for example one per chain ID and
+// message kind, keeps signatures from being replayed across purposes.
+//
+// Tags should follow the recommendations of RFC 9380, section 3.1, and
+// include the name of the application and of the ciphersuite, for example
+// "TENDERDASH-V1-<chain-id>-VOTE-BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_".
+//
+// The dashpay/bls-signatures library only supports the tags of the standard
+// schemes, so domain schemes are always computed in pure Go.
+type DomainScheme struct {
+	scheme Scheme
+	dst    string
+}
+
+// NewDomainScheme returns the scheme that signs like scheme, but with the
+// domain separation tag dst. The tag must be between 1 and 255 bytes long.
+func NewDomainScheme(scheme Scheme, dst string) (DomainScheme, error) {
+	if !scheme.valid() {
+		return DomainScheme{}, fmt.Errorf("%w: %d", errUnknownScheme, uint8(scheme))
+	}
+	if err := validateDST(dst); err != nil {
+		return DomainScheme{}, err
+	}
+	return DomainScheme{scheme: scheme, dst: dst}, nil
+}
+
+// Scheme returns the scheme the domain scheme is derived from.
+func (s DomainScheme) Scheme() Scheme {
+	return s.scheme
+}
+
+// DST returns the domain separation tag of the scheme.
+func (s DomainScheme) DST() string {
+	return s.dst
+}
+
+// String returns the name of the underlying scheme and the tag.
+func (s DomainScheme) String() string {
+	return fmt.Sprintf("%s(%s)", s.scheme, s.dst)
+}
+
+// Sign produces a signature of msg under the scheme.
+func (s DomainScheme) Sign(privKey PrivKey, msg []byte) ([]byte, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return withScalar(privKey, func(sk *big.Int) ([]byte, error) {
+		if s.scheme == SchemeAugmented {
+			pk, err := goBackend{}.publicKey(privKey)
+			if err != nil {
+				return nil, err
+			}
+			msg = s.scheme.augment(pk, msg)
+		}
+		return coreSign(sk, msg, s.dst)
+	})
+}
+
+// Verify reports whether sig is a signature of msg by pubKey under the scheme.
+// As with Scheme.Verify, the public key at infinity never verifies.
+func (s DomainScheme) Verify(pubKey PubKey, msg []byte, sig []byte) bool {
+	if s.validate() != nil || len(sig) != SignatureSize || isInfinity(pubKey) {
+		return false
+	}
+	return coreVerify(pubKey, s.scheme.augment(pubKey, msg), sig, s.dst)
+}
+
+// AggregateVerify is Scheme.AggregateVerify under the tag of the scheme.
+func (s DomainScheme) AggregateVerify(pubKeys []crypto.PubKey, msgs [][]byte, sig []byte) bool {
+	if s.validate() != nil || len(pubKeys) == 0 || len(pubKeys) != len(msgs) || len(sig) != SignatureSize {
+		return false
+	}
+	if s.scheme == SchemeBasic && !distinctMessages(msgs) {
+		return false
+	}
+	pks, err := pubKeysBytes(pubKeys)
+	if err != nil || checkNotInfinity(pks) != nil {
+		return false
+	}
+	return coreAggregateVerify(s.scheme, s.dst, pks, msgs, sig)
+}
+
+// validate checks that the scheme was created with NewDomainScheme.
+func (s DomainScheme) validate() error {
+	if !s.scheme.valid() {
+		return errUnknownScheme
+	}
+	return validateDST(s.dst)
+}
+
+// validateDST checks the length of a domain separation tag.
+func validateDST(dst string) error {
+	if len(dst) == 0 || len(dst) > maxDSTSize {
+		return fmt.Errorf("%w: size %d is out of range [1, %d]", errInvalidDST, len(dst), maxDSTSize)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package bls12381
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+func TestDomainSchemeStandardDST(t *testing.T) {
+	privKey := GenPrivKey()
+	pubKey := privKey.PubKey().(PubKey)
+	msg := crypto.CRandBytes(32)
+	for _, scheme := range []Scheme{SchemeBasic, SchemeAugmented, SchemePoP} {
+		domainScheme, err := NewDomainScheme(scheme, scheme.dst())
+		require.NoError(t, err)
+		want, err := scheme.Sign(privKey, msg)
+		require.NoError(t, err)
+		got, err := domainScheme.Sign(privKey, msg)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, scheme)
+		assert.True(t, domainScheme.Verify(pubKey, msg, want), scheme)
+	}
+}
+
+func TestDomainSchemeSeparation(t *testing.T) {
+	privKeys := []PrivKey{GenPrivKey(), GenPrivKey()}
+	pubKeys := []crypto.PubKey{privKeys[0].PubKey(), privKeys[1].PubKey()}
+	msg := crypto.CRandBytes(32)
+
+	for _, scheme := range []Scheme{SchemeBasic, SchemeAugmented, SchemePoP} {
+		votes, err := NewDomainScheme(scheme, "TENDERDASH-V1-chain-a-VOTE-"+scheme.dst())
+		require.NoError(t, err)
+		proposals, err := NewDomainScheme(scheme, "TENDERDASH-V1-chain-a-PROPOSAL-"+scheme.dst())
+		require.NoError(t, err)
+		assert.Equal(t, scheme, votes.Scheme())
+
+		sig, err := votes.Sign(privKeys[0], msg)
+		require.NoError(t, err)
+		assert.True(t, votes.Verify(pubKeys[0].(PubKey), msg, sig), votes)
+		assert.False(t, votes.Verify(pubKeys[1].(PubKey), msg, sig), votes)
+		assert.False(t, proposals.Verify(pubKeys[0].(PubKey), msg, sig), votes)
+		assert.False(t, scheme.Verify(pubKeys[0].(PubKey), msg, sig), votes)
+
+		msgs := [][]byte{msg, append(msg, 1)}
+		sig2, err := votes.Sign(privKeys[1], msgs[1])
+		require.NoError(t, err)
+		aggregate, err := AggregateSignatures([][]byte{sig, sig2})
+		require.NoError(t, err)
+		assert.True(t, votes.AggregateVerify(pubKeys, msgs, aggregate), votes)
+		assert.False(t, proposals.AggregateVerify(pubKeys, msgs, aggregate), votes)
+	}
+
+	basic, err := NewDomainScheme(SchemeBasic, "TENDERDASH-V1-chain-a-VOTE-"+basicSchemeDST)
+	require.NoError(t, err)
+	sigs := make([][]byte, len(privKeys))
+	for i, privKey := range privKeys {
+		sigs[i], err = basic.Sign(privKey, msg)
+		require.NoError(t, err)
+	}
+	aggregate, err := AggregateSignatures(sigs)
+	require.NoError(t, err)
+	assert.False(t, basic.AggregateVerify(pubKeys, [][]byte{msg, msg}, aggregate), "the basic scheme needs distinct messages")
+}
+
+func TestDomainSchemeInfinityPubKey(t *testing.T) {
+	infinitySig := append([]byte{0xc0}, make([]byte, SignatureSize-1)...)
+	privKey := GenPrivKey()
+	msgs := [][]byte{[]byte("signed"), []byte("not signed")}
+	for _, scheme := range []Scheme{SchemeBasic, SchemeAugmented, SchemePoP} {
+		domainScheme, err := NewDomainScheme(scheme, "TENDERDASH-V1-chain-a-VOTE-"+scheme.dst())
+		require.NoError(t, err)
+		assert.False(t, domainScheme.Verify(infinityPubKey(), msgs[0], infinitySig), scheme)
+
+		sig, err := domainScheme.Sign(privKey, msgs[0])
+		require.NoError(t, err)
+		pubKeys := []crypto.PubKey{privKey.PubKey(), infinityPubKey()}
+		assert.False(t, domainScheme.AggregateVerify(pubKeys, msgs, sig), scheme)
+	}
+}
+
+func TestNewDomainScheme(t *testing.T) {
+	_, err := NewDomainScheme(SchemeBasic, "")
+	assert.ErrorIs(t, err, errInvalidDST)
+	_, err = NewDomainScheme(SchemeBasic, strings.Repeat("a", maxDSTSize+1))
+	assert.ErrorIs(t, err, errInvalidDST)
+	_, err = NewDomainScheme(Scheme(42), basicSchemeDST)
+	assert.ErrorIs(t, err, errUnknownScheme)
+	scheme, err := NewDomainScheme(SchemePoP, strings.Repeat("a", maxDSTSize))
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("a", maxDSTSize), scheme.DST())
+
+	// the zero value has no tag and can neither sign nor verify
+	privKey := GenPrivKey()
+	_, err = DomainScheme{}.Sign(privKey, []byte("msg"))
+	assert.ErrorIs(t, err, errInvalidDST)
+	sig, err := privKey.Sign([]byte("msg"))
+	require.NoError(t, err)
+	assert.False(t, DomainScheme{}.Verify(privKey.PubKey().(PubKey), []byte("msg"), sig))
+}
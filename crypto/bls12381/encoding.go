@@ -0,0 +1,296 @@
+package bls12381
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Encoding is a set of serialization formats of compressed BLS12-381 points.
+type Encoding uint8
+
+const (
+	// EncodingIETF is the format of the IETF BLS signature draft and zcash,
+	// which flags compression, the point at infinity and the sign of y in the
+	// three most significant bits. Keys and signatures of this package use it.
+	EncodingIETF Encoding = 1 << iota
+	// EncodingLegacy is the format of bls-signatures before v1, which dashd
+	// used until v19. It only flags the sign of y, in the most significant bit,
+	// encodes the point at infinity as zeros and orders the two halves of
+	// G2 coordinates the other way around.
+	EncodingLegacy
+
+	// encodingCompressionBit, encodingInfinityBit and encodingSignBit are the
+	// flags of the first byte of an IETF encoding.
+	encodingCompressionBit = 0x80
+	encodingInfinityBit    = 0x40
+	encodingSignBit        = 0x20
+	// encodingLegacySignBit is the flag of the first byte of a legacy encoding.
+	encodingLegacySignBit = 0x80
+	// encodingFlagsMask covers all the flags of either encoding.
+	encodingFlagsMask = 0xe0
+)
+
+var (
+	// ErrAmbiguousEncoding is returned when a point is valid in more than one
+	// of the accepted encodings and decodes to a different point in each.
+	ErrAmbiguousEncoding = errors.New("ambiguous encoding")
+
+	errInvalidLegacyEncoding = errors.New("invalid legacy encoding")
+	errInvalidIETFEncoding   = errors.New("invalid ietf encoding")
+)
+
+// String returns the names of the encodings of the set.
+func (e Encoding) String() string {
+	var names []string
+	if e&EncodingIETF != 0 {
+		names = append(names, "ietf")
+	}
+	if e&EncodingLegacy != 0 {
+		names = append(names, "legacy")
+	}
+	if rest := e &^ (EncodingIETF | EncodingLegacy); rest != 0 || len(names) == 0 {
+		names = append(names, fmt.Sprintf("Encoding(%d)", uint8(rest)))
+	}
+	return strings.Join(names, "|")
+}
+
+// DetectPubKeyEncoding returns the encodings b is a valid public key in, see
+// PubKey.Validate.
+//
+// The most significant bit is the compression flag of the IETF encoding and
+// the sign of y in the legacy one, so a legacy public key with that bit set is
+// also the IETF public key of the opposite point, and both encodings are
+// returned. Such public keys can only be told apart by where they come from,
+// and DecodePubKey rejects them when it accepts both encodings.
+func DetectPubKeyEncoding(b []byte) (Encoding, error) {
+	return detectEncoding(b, PubKeySize, func(b []byte) error { return PubKey(b).Validate() })
+}
+
+// DetectSignatureEncoding returns the encodings b is a valid signature in, see
+// Signature.Validate and DetectPubKeyEncoding.
+func DetectSignatureEncoding(b []byte) (Encoding, error) {
+	return detectEncoding(b, SignatureSize, func(b []byte) error { return Signature(b).Validate() })
+}
+
+// DecodePubKey decodes a public key in any of the accepted encodings and
+// validates it. The returned key is in the IETF encoding and does not share
+// memory with b.
+//
+// A key valid in both the IETF and the legacy encoding, see
+// DetectPubKeyEncoding, stands for two different points, and decoding it with
+// both accepted fails with ErrAmbiguousEncoding.
+func DecodePubKey(b []byte, accept Encoding) (PubKey, error) {
+	pubKey, err := decodeEncoded(b, PubKeySize, accept, func(b []byte) error { return PubKey(b).Validate() })
+	if err != nil {
+		return nil, err
+	}
+	return pubKey, nil
+}
+
+// DecodeSignature decodes a signature in any of the accepted encodings and
+// validates it, see DecodePubKey. The returned signature is in the IETF
+// encoding and does not share memory with b.
+func DecodeSignature(b []byte, accept Encoding) (Signature, error) {
+	sig, err := decodeEncoded(b, SignatureSize, accept, func(b []byte) error { return Signature(b).Validate() })
+	if err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// VerifyEncoded is PubKey.VerifySignature for a public key and a signature in
+// any of the accepted encodings. Unlike DecodePubKey and DecodeSignature, it
+// accepts ambiguous inputs and tries each of their decodings.
+func VerifyEncoded(pubKey, msg, sig []byte, accept Encoding) bool {
+	pubKeys, err := decodeCandidates(pubKey, PubKeySize, accept, func(b []byte) error { return PubKey(b).Validate() })
+	if err != nil {
+		return false
+	}
+	sigs, err := decodeCandidates(sig, SignatureSize, accept, func(b []byte) error { return Signature(b).Validate() })
+	if err != nil {
+		return false
+	}
+	for _, pk := range pubKeys {
+		for _, signature := range sigs {
+			if PubKey(pk).VerifySignature(msg, signature) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PubKeyFromLegacy converts a public key from the legacy encoding and
+// validates it.
+func PubKeyFromLegacy(b []byte) (PubKey, error) {
+	return DecodePubKey(b, EncodingLegacy)
+}
+
+// LegacyBytes returns the public key in the legacy encoding.
+func (pubKey PubKey) LegacyBytes() ([]byte, error) {
+	if len(pubKey) != PubKeySize {
+		return nil, fmt.Errorf("public key has wrong size %d: %w", len(pubKey), ErrPubKeyInvalidSize)
+	}
+	return ietfToLegacy(pubKey)
+}
+
+// SignatureFromLegacy converts a signature from the legacy encoding and
+// validates it.
+func SignatureFromLegacy(b []byte) (Signature, error) {
+	return DecodeSignature(b, EncodingLegacy)
+}
+
+// LegacyBytes returns the signature in the legacy encoding.
+func (sig Signature) LegacyBytes() ([]byte, error) {
+	if len(sig) != SignatureSize {
+		return nil, fmt.Errorf("signature has wrong size %d: %w", len(sig), errSignatureInvalidSize)
+	}
+	return ietfToLegacy(sig)
+}
+
+// detectEncoding returns the encodings b is valid in according to validate,
+// which checks IETF encodings.
+func detectEncoding(b []byte, size int, validate func([]byte) error) (Encoding, error) {
+	if len(b) != size {
+		return 0, fmt.Errorf("wrong size %d, expected %d", len(b), size)
+	}
+	var encodings Encoding
+	ietfErr := validate(b)
+	if ietfErr == nil {
+		encodings |= EncodingIETF
+	}
+	converted, err := legacyToIETF(b)
+	if err == nil {
+		err = validate(converted)
+	}
+	if err == nil {
+		encodings |= EncodingLegacy
+	}
+	if encodings == 0 {
+		return 0, fmt.Errorf("not valid in any encoding: %w", ietfErr)
+	}
+	return encodings, nil
+}
+
+// decodeEncoded converts b from the accepted encoding it is valid in according
+// to validate, which checks IETF encodings, and fails if there are several.
+func decodeEncoded(b []byte, size int, accept Encoding, validate func([]byte) error) ([]byte, error) {
+	candidates, err := decodeCandidates(b, size, accept, validate)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) > 1 {
+		return nil, fmt.Errorf("valid in %s: %w", accept&(EncodingIETF|EncodingLegacy), ErrAmbiguousEncoding)
+	}
+	return candidates[0], nil
+}
+
+// decodeCandidates converts b from each accepted encoding it is valid in
+// according to validate, which checks IETF encodings. It returns at least one
+// conversion or the error of the first accepted encoding.
+func decodeCandidates(b []byte, size int, accept Encoding, validate func([]byte) error) ([][]byte, error) {
+	if len(b) != size {
+		return nil, fmt.Errorf("wrong size %d, expected %d", len(b), size)
+	}
+	if accept&(EncodingIETF|EncodingLegacy) == 0 {
+		return nil, fmt.Errorf("no known encoding in %s", accept)
+	}
+	var (
+		candidates [][]byte
+		err        error
+	)
+	if accept&EncodingIETF != 0 {
+		if err = validate(b); err == nil {
+			candidates = append(candidates, append([]byte(nil), b...))
+		}
+	}
+	if accept&EncodingLegacy != 0 {
+		converted, legacyErr := legacyToIETF(b)
+		if legacyErr == nil {
+			legacyErr = validate(converted)
+		}
+		if legacyErr == nil {
+			candidates = append(candidates, converted)
+		} else if err == nil {
+			err = legacyErr
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// legacyToIETF converts a compressed G1 or G2 point from the legacy encoding to
+// the IETF one.
+func legacyToIETF(b []byte) ([]byte, error) {
+	if len(b) == 0 || b[0]&^encodingLegacySignBit&encodingFlagsMask != 0 {
+		return nil, errInvalidLegacyEncoding
+	}
+	if isZero(b) {
+		out := make([]byte, len(b))
+		out[0] = encodingCompressionBit | encodingInfinityBit
+		return out, nil
+	}
+	flagless := append([]byte(nil), b...)
+	flagless[0] &^= encodingLegacySignBit
+	out := swapHalves(flagless)
+	if out[0]&encodingFlagsMask != 0 {
+		return nil, errInvalidLegacyEncoding
+	}
+	out[0] |= encodingCompressionBit
+	if b[0]&encodingLegacySignBit != 0 {
+		out[0] |= encodingSignBit
+	}
+	return out, nil
+}
+
+// ietfToLegacy converts a compressed G1 or G2 point from the IETF encoding to
+// the legacy one.
+func ietfToLegacy(b []byte) ([]byte, error) {
+	if len(b) == 0 || b[0]&encodingCompressionBit == 0 {
+		return nil, errInvalidIETFEncoding
+	}
+	if b[0]&encodingInfinityBit != 0 {
+		if !isInfinity(b) {
+			return nil, errInvalidIETFEncoding
+		}
+		return make([]byte, len(b)), nil
+	}
+	flagless := append([]byte(nil), b...)
+	flagless[0] &^= encodingFlagsMask
+	out := swapHalves(flagless)
+	if out[0]&encodingFlagsMask != 0 {
+		return nil, errInvalidIETFEncoding
+	}
+	if b[0]&encodingSignBit != 0 {
+		out[0] |= encodingLegacySignBit
+	}
+	return out, nil
+}
+
+// swapHalves returns a copy of b with the two coordinates of a G2 point swapped,
+// as the legacy encoding orders them the other way around. G1 points are
+// copied as they are.
+func swapHalves(b []byte) []byte {
+	out := make([]byte, len(b))
+	if len(b) != SignatureSize {
+		copy(out, b)
+		return out
+	}
+	half := len(b) / 2
+	copy(out, b[half:])
+	copy(out[half:], b[:half])
+	return out
+}
+
+// isZero reports whether all the bytes of b are zero.
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,285 @@
+package bls12381
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	bls12 "github.com/kilic/bls12-381"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+const (
+	// g1Hex and g2Hex are the IETF encodings of the generators of G1 and G2,
+	// whose y coordinates have the sign bit clear.
+	g1Hex = "97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb"
+	g2Hex = "93e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e" +
+		"024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8"
+	// legacyG1Hex and legacyG2Hex are the legacy encodings of the generators.
+	legacyG1Hex = "17f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb"
+	legacyG2Hex = "024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8" +
+		"13e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e"
+)
+
+func TestEncodingGeneratorVectors(t *testing.T) {
+	g1, g2 := bls12.NewG1(), bls12.NewG2()
+	require.Equal(t, g1Hex, hex.EncodeToString(g1.ToCompressed(g1.One())))
+	require.Equal(t, g2Hex, hex.EncodeToString(g2.ToCompressed(g2.One())))
+
+	negG1 := g1.ToCompressed(g1.Neg(g1.New(), g1.One()))
+	negG2 := g2.ToCompressed(g2.Neg(g2.New(), g2.One()))
+	testCases := []struct {
+		name   string
+		ietf   string
+		legacy string
+	}{
+		{name: "g1", ietf: g1Hex, legacy: legacyG1Hex},
+		{name: "-g1", ietf: hex.EncodeToString(negG1), legacy: "97" + legacyG1Hex[2:]},
+		{name: "g2", ietf: g2Hex, legacy: legacyG2Hex},
+		{name: "-g2", ietf: hex.EncodeToString(negG2), legacy: "82" + legacyG2Hex[2:]},
+		{name: "g1 infinity", ietf: "c0" + zeroHex(PubKeySize-1), legacy: zeroHex(PubKeySize)},
+		{name: "g2 infinity", ietf: "c0" + zeroHex(SignatureSize-1), legacy: zeroHex(SignatureSize)},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ietf, legacy := mustDecodeHex(t, tc.ietf), mustDecodeHex(t, tc.legacy)
+			converted, err := ietfToLegacy(ietf)
+			require.NoError(t, err)
+			assert.Equal(t, tc.legacy, hex.EncodeToString(converted))
+			converted, err = legacyToIETF(legacy)
+			require.NoError(t, err)
+			assert.Equal(t, tc.ietf, hex.EncodeToString(converted))
+		})
+	}
+}
+
+func TestPubKeyLegacyEncoding(t *testing.T) {
+	for i := 0; i < 16; i++ {
+		privKey := GenPrivKey()
+		pubKey := privKey.PubKey().(PubKey)
+		legacy, err := pubKey.LegacyBytes()
+		require.NoError(t, err)
+		assert.Equal(t, legacyPubKey(t, pubKey), legacy)
+
+		decoded, err := PubKeyFromLegacy(legacy)
+		require.NoError(t, err)
+		assert.Equal(t, pubKey, decoded)
+		// legacy keys with the sign bit set are the IETF keys of the opposite
+		// points, and IETF keys without it are valid legacy keys
+		decoded, err = DecodePubKey(legacy, EncodingIETF|EncodingLegacy)
+		if legacy[0]&encodingLegacySignBit != 0 {
+			assert.ErrorIs(t, err, ErrAmbiguousEncoding)
+		} else {
+			require.NoError(t, err)
+			assert.Equal(t, pubKey, decoded)
+		}
+		decoded, err = DecodePubKey(pubKey, EncodingIETF|EncodingLegacy)
+		if pubKey[0]&encodingSignBit == 0 {
+			assert.ErrorIs(t, err, ErrAmbiguousEncoding)
+		} else {
+			require.NoError(t, err)
+			assert.Equal(t, pubKey, decoded)
+		}
+
+		encodings, err := DetectPubKeyEncoding(legacy)
+		require.NoError(t, err)
+		assert.True(t, encodings&EncodingLegacy != 0)
+		encodings, err = DetectPubKeyEncoding(pubKey)
+		require.NoError(t, err)
+		assert.True(t, encodings&EncodingIETF != 0)
+
+		msg := crypto.CRandBytes(32)
+		sig, err := privKey.Sign(msg)
+		require.NoError(t, err)
+		legacySig, err := Signature(sig).LegacyBytes()
+		require.NoError(t, err)
+		assert.True(t, VerifyEncoded(legacy, msg, legacySig, EncodingLegacy))
+		assert.True(t, VerifyEncoded(pubKey, msg, sig, EncodingIETF))
+		assert.True(t, VerifyEncoded(pubKey, msg, legacySig, EncodingIETF|EncodingLegacy))
+		assert.True(t, VerifyEncoded(legacy, msg, legacySig, EncodingIETF|EncodingLegacy))
+		assert.True(t, VerifyEncoded(legacy, msg, sig, EncodingIETF|EncodingLegacy))
+		assert.False(t, VerifyEncoded(pubKey, msg, legacySig, EncodingIETF))
+		assert.False(t, VerifyEncoded(legacy, msg, sig, EncodingLegacy))
+	}
+}
+
+func TestSignatureLegacyEncoding(t *testing.T) {
+	// signatures of the basic, augmented and pop schemes produced by the
+	// dashpay/bls-signatures library, see TestSchemeVectors
+	for _, sigHex := range []string{
+		"96ba34fac33c7f129d602a0bc8a3d43f9abc014eceaab7359146b4b150e57b808645738f35671e9e10e0d862a30cab70074eb5831d13e6a5b162d01eebe687d0164adbd0a864370a7c222a2768d7704da254f1bf1823665bc2361f9dd8c00e99",
+		"8180f02ccb72e922b152fcedbe0e1d195210354f70703658e8e08cbebf11d4970eab6ac3ccf715f3fb876df9a9797abd0c1af61aaeadc92c2cfe5c0a56c146cc8c3f7151a073cf5f16df38246724c4aed73ff30ef5daa6aacaed1a26ecaa336b",
+		"a69036bc11ae5efcbf6180afe39addde7e27731ec40257bfdc3c37f17b8df68306a34ebd10e9e32a35253750df5c87c2142f8207e8d5654712b4e554f585fb6846ff3804e429a9f8a1b4c56b75d0869ed67580d789870babe2c7c8a9d51e7b2a",
+	} {
+		sig := Signature(mustDecodeHex(t, sigHex))
+		legacy, err := sig.LegacyBytes()
+		require.NoError(t, err)
+		assert.Equal(t, legacySignature(t, sig), legacy)
+
+		decoded, err := SignatureFromLegacy(legacy)
+		require.NoError(t, err)
+		assert.Equal(t, sig, decoded)
+		decoded, err = DecodeSignature(legacy, EncodingIETF|EncodingLegacy)
+		require.NoError(t, err)
+		assert.Equal(t, sig, decoded)
+
+		encodings, err := DetectSignatureEncoding(legacy)
+		require.NoError(t, err)
+		assert.Equal(t, EncodingLegacy, encodings)
+		encodings, err = DetectSignatureEncoding(sig)
+		require.NoError(t, err)
+		assert.Equal(t, EncodingIETF, encodings)
+	}
+}
+
+func TestDashdLegacyVectors(t *testing.T) {
+	// points serialized by dashd before it switched to the IETF encoding, from
+	// RPC results kept in the tests of github.com/dashpay/dashd-go v0.24.1: the
+	// operator keys and the quorum public key of a testnet `quorum info` at
+	// height 264072, the operator key of a `protx update_service` call and the
+	// signature of a `quorum sign` call on regtest
+	pubKeys := []string{
+		"0db6da5d8ee9fb8925f0818df7553062bf35ec9d62114144bc395980c29fcd06b738beca63faf265d7480106fc6cceea",
+		"0634f8b926631cb2b14c81720c6130b3f6f5429da1c9dc9c33918b2474b7ffff239caa9b59c7b1a782565052232d052a",
+		"0644ff153b9b92c6a59e2adf4ef0b9836f7f6af05fe432ffdcb69bc9e300a2a70af4a8d9fc61323f6b81074d740033d2",
+		"084ceaabfe23865823aa696258245d8f94144fc33fb558528cd1742ef8f033d7b8c701d19cd6a561522c9e8d82bf7283",
+	}
+	for _, pubKeyHex := range pubKeys {
+		legacy := mustDecodeHex(t, pubKeyHex)
+		encodings, err := DetectPubKeyEncoding(legacy)
+		require.NoError(t, err)
+		assert.Equal(t, EncodingLegacy, encodings, pubKeyHex)
+
+		pubKey, err := PubKeyFromLegacy(legacy)
+		require.NoError(t, err)
+		require.NoError(t, pubKey.Validate())
+		decoded, err := DecodePubKey(legacy, EncodingIETF|EncodingLegacy)
+		require.NoError(t, err)
+		assert.Equal(t, pubKey, decoded)
+		_, err = DecodePubKey(legacy, EncodingIETF)
+		assert.Error(t, err)
+		encoded, err := pubKey.LegacyBytes()
+		require.NoError(t, err)
+		assert.Equal(t, pubKeyHex, hex.EncodeToString(encoded))
+	}
+
+	sigHex := "9716545a0c28ff70900a71fabbadf3c13e4ae562032122902405365f1ebf3da813c8a97d765eb8b167ff339c1638550c" +
+		"13822217cf06b609ba6a78f0035684ca7b4afdb7146ce74a30cfb6770f852aade8c27ffec67c79f85be31964573fb51c"
+	legacy := mustDecodeHex(t, sigHex)
+	encodings, err := DetectSignatureEncoding(legacy)
+	require.NoError(t, err)
+	assert.Equal(t, EncodingLegacy, encodings)
+	sig, err := SignatureFromLegacy(legacy)
+	require.NoError(t, err)
+	require.NoError(t, sig.Validate())
+	decoded, err := DecodeSignature(legacy, EncodingIETF|EncodingLegacy)
+	require.NoError(t, err)
+	assert.Equal(t, sig, decoded)
+	encoded, err := sig.LegacyBytes()
+	require.NoError(t, err)
+	assert.Equal(t, sigHex, hex.EncodeToString(encoded))
+}
+
+func TestDetectPubKeyEncoding(t *testing.T) {
+	g1 := bls12.NewG1()
+	negG1 := g1.ToCompressed(g1.Neg(g1.New(), g1.One()))
+	testCases := []struct {
+		name      string
+		pubKey    []byte
+		encodings Encoding
+	}{
+		{name: "ietf with sign bit", pubKey: negG1, encodings: EncodingIETF},
+		{name: "legacy without sign bit", pubKey: mustDecodeHex(t, legacyG1Hex), encodings: EncodingLegacy},
+		{name: "ambiguous", pubKey: mustDecodeHex(t, g1Hex), encodings: EncodingIETF | EncodingLegacy},
+		{name: "infinity", pubKey: mustDecodeHex(t, "c0"+zeroHex(PubKeySize-1))},
+		{name: "legacy infinity", pubKey: make([]byte, PubKeySize)},
+		{name: "legacy with flags", pubKey: mustDecodeHex(t, "57"+legacyG1Hex[2:])},
+		{name: "short", pubKey: mustDecodeHex(t, g1Hex)[1:]},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			encodings, err := DetectPubKeyEncoding(tc.pubKey)
+			if tc.encodings == 0 {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.encodings, encodings)
+		})
+	}
+
+	_, err := DecodePubKey(mustDecodeHex(t, g1Hex), 0)
+	assert.Error(t, err)
+	_, err = DecodePubKey(mustDecodeHex(t, legacyG1Hex), EncodingIETF)
+	assert.ErrorIs(t, err, ErrPubKeyInvalidEncoding)
+	_, err = DecodePubKey(mustDecodeHex(t, g1Hex), EncodingIETF|EncodingLegacy)
+	assert.ErrorIs(t, err, ErrAmbiguousEncoding)
+	decoded, err := DecodePubKey(mustDecodeHex(t, g1Hex), EncodingIETF)
+	require.NoError(t, err)
+	assert.Equal(t, PubKey(mustDecodeHex(t, g1Hex)), decoded)
+	_, err = PubKey(mustDecodeHex(t, legacyG1Hex)).LegacyBytes()
+	assert.Error(t, err)
+}
+
+func TestEncodingString(t *testing.T) {
+	assert.Equal(t, "ietf", EncodingIETF.String())
+	assert.Equal(t, "legacy", EncodingLegacy.String())
+	assert.Equal(t, "ietf|legacy", (EncodingIETF | EncodingLegacy).String())
+	assert.Equal(t, "Encoding(0)", Encoding(0).String())
+	assert.Equal(t, "ietf|Encoding(4)", (EncodingIETF | 4).String())
+}
+
+// legacyPubKey encodes the public key in the legacy encoding from the
+// coordinates of its point.
+func legacyPubKey(t *testing.T, pubKey PubKey) []byte {
+	t.Helper()
+	g1 := bls12.NewG1()
+	p, err := g1.FromCompressed(pubKey)
+	require.NoError(t, err)
+	uncompressed := g1.ToUncompressed(p)
+	x, y := uncompressed[:48], uncompressed[48:]
+	legacy := append([]byte(nil), x...)
+	if isLargerThanNegation(y) {
+		legacy[0] |= 0x80
+	}
+	return legacy
+}
+
+// legacySignature encodes the signature in the legacy encoding from the
+// coordinates of its point.
+func legacySignature(t *testing.T, sig Signature) []byte {
+	t.Helper()
+	g2 := bls12.NewG2()
+	p, err := g2.FromCompressed(sig)
+	require.NoError(t, err)
+	uncompressed := g2.ToUncompressed(p)
+	// the uncompressed encoding is x.c1 || x.c0 || y.c1 || y.c0
+	x1, x0, y1, y0 := uncompressed[:48], uncompressed[48:96], uncompressed[96:144], uncompressed[144:]
+	legacy := append(append([]byte(nil), x0...), x1...)
+	if isLargerThanNegation(y1) || isZero(y1) && isLargerThanNegation(y0) {
+		legacy[0] |= 0x80
+	}
+	return legacy
+}
+
+// isLargerThanNegation reports whether the field element y is larger than -y.
+func isLargerThanNegation(y []byte) bool {
+	half := new(big.Int).Rsh(fieldModulus, 1)
+	return new(big.Int).SetBytes(y).Cmp(half) > 0
+}
+
+func zeroHex(size int) string {
+	return hex.EncodeToString(make([]byte, size))
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
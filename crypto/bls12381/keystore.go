@@ -0,0 +1,383 @@
+package bls12381
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+// KeystoreVersion is the version of the keystore format, as defined by
+// EIP-2335.
+const KeystoreVersion = 4
+
+// KeystoreKDF is the key derivation function that derives the encryption key
+// of a keystore from its password.
+type KeystoreKDF string
+
+const (
+	// KeystoreScrypt derives keys with scrypt, n = 2^18, r = 8, p = 1.
+	KeystoreScrypt KeystoreKDF = "scrypt"
+	// KeystorePBKDF2 derives keys with PBKDF2-HMAC-SHA256 and 2^18 iterations.
+	KeystorePBKDF2 KeystoreKDF = "pbkdf2"
+
+	keystoreChecksum = "sha256"
+	keystoreCipher   = "aes-128-ctr"
+	keystorePRF      = "hmac-sha256"
+	// keystoreKeySize is the size of the derived key, whose first half is the
+	// AES key and second half the checksum key.
+	keystoreKeySize  = 32
+	keystoreSaltSize = 32
+	// keystoreCost is the default scrypt n and PBKDF2 iteration count.
+	keystoreCost = 1 << 18
+	// keystoreMaxCost bounds the cost of the documents DecryptKeystore reads.
+	keystoreMaxCost = 1 << 20
+	// keystoreMaxScryptCost bounds n*r*p of the scrypt documents
+	// DecryptKeystore reads, which caps the memory scrypt uses at
+	// 128*n*r*p = 1 GiB, that of n = 2^20 and r = 8.
+	keystoreMaxScryptCost = keystoreMaxCost * 8
+)
+
+var (
+	// ErrKeystorePassword is returned when the checksum of a keystore does not
+	// match the password.
+	ErrKeystorePassword = errors.New("invalid keystore password")
+
+	errInvalidKeystore = errors.New("invalid keystore")
+)
+
+// KeystoreParams are the optional settings of EncryptKeystore.
+type KeystoreParams struct {
+	// KDF defaults to KeystoreScrypt.
+	KDF KeystoreKDF
+	// Description is stored in plaintext to tell keystores apart.
+	Description string
+	// Path is the EIP-2334 derivation path of the key, if any.
+	Path string
+}
+
+// Keystore is the JSON document of an EIP-2335 keystore. Only the secret is
+// encrypted.
+type Keystore struct {
+	Crypto      KeystoreCrypto `json:"crypto"`
+	Description string         `json:"description,omitempty"`
+	PubKey      keystoreHex    `json:"pubkey"`
+	// ThresholdPublicKey is the public key of the quorum of a keystore that
+	// wraps quorum keys. It extends EIP-2335.
+	ThresholdPublicKey keystoreHex `json:"threshold_pubkey,omitempty"`
+	Path               string      `json:"path"`
+	UUID               string      `json:"uuid"`
+	Version            int         `json:"version"`
+}
+
+// KeystoreCrypto holds the modules that encrypt the secret of a keystore.
+type KeystoreCrypto struct {
+	KDF      KeystoreModule `json:"kdf"`
+	Checksum KeystoreModule `json:"checksum"`
+	Cipher   KeystoreModule `json:"cipher"`
+}
+
+// KeystoreModule is a function of a keystore with its parameters and message.
+type KeystoreModule struct {
+	Function string         `json:"function"`
+	Params   keystoreParams `json:"params"`
+	Message  keystoreHex    `json:"message"`
+}
+
+// keystoreParams is the union of the parameters of all supported modules.
+type keystoreParams struct {
+	DKLen int         `json:"dklen,omitempty"`
+	N     int         `json:"n,omitempty"`
+	R     int         `json:"r,omitempty"`
+	P     int         `json:"p,omitempty"`
+	C     int         `json:"c,omitempty"`
+	PRF   string      `json:"prf,omitempty"`
+	Salt  keystoreHex `json:"salt,omitempty"`
+	IV    keystoreHex `json:"iv,omitempty"`
+}
+
+// keystoreHex is hex encoded without prefix in keystores.
+type keystoreHex []byte
+
+func (h keystoreHex) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(h)), nil
+}
+
+func (h *keystoreHex) UnmarshalText(text []byte) error {
+	b, err := hex.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	*h = b
+	return nil
+}
+
+// EncryptKeystore encrypts privKey with password into a keystore document.
+func EncryptKeystore(privKey PrivKey, password string, params KeystoreParams) ([]byte, error) {
+	ks, err := newKeystore(rand.Reader, privKey, password, params, keystoreCost)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(ks)
+}
+
+// DecryptKeystore decrypts the private key of a keystore document with
+// password and checks that it matches the public key of the document.
+func DecryptKeystore(data []byte, password string) (PrivKey, error) {
+	var ks Keystore
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidKeystore, err)
+	}
+	return ks.Decrypt(password)
+}
+
+// EncryptQuorumKeysKeystore encrypts the private key share of keys with
+// password into a keystore document that also holds the threshold public key.
+func EncryptQuorumKeysKeystore(keys crypto.QuorumKeys, password string, params KeystoreParams) ([]byte, error) {
+	ks, err := newQuorumKeysKeystore(rand.Reader, keys, password, params, keystoreCost)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(ks)
+}
+
+// DecryptQuorumKeysKeystore decrypts the quorum keys of a keystore document
+// produced by EncryptQuorumKeysKeystore. Unlike DecryptKeystore, it requires
+// the public key, which is the public key share of the quorum keys.
+func DecryptQuorumKeysKeystore(data []byte, password string) (crypto.QuorumKeys, error) {
+	var ks Keystore
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return crypto.QuorumKeys{}, fmt.Errorf("%w: %v", errInvalidKeystore, err)
+	}
+	if len(ks.PubKey) == 0 {
+		return crypto.QuorumKeys{}, fmt.Errorf("%w: missing public key", errInvalidKeystore)
+	}
+	if err := PubKey(ks.ThresholdPublicKey).Validate(); err != nil {
+		return crypto.QuorumKeys{}, fmt.Errorf("%w: threshold public key: %v", errInvalidKeystore, err)
+	}
+	privKey, err := ks.Decrypt(password)
+	if err != nil {
+		return crypto.QuorumKeys{}, err
+	}
+	return crypto.QuorumKeys{
+		PrivKey:            privKey,
+		PubKey:             PubKey(ks.PubKey),
+		ThresholdPublicKey: PubKey(ks.ThresholdPublicKey),
+	}, nil
+}
+
+// Decrypt decrypts the private key of the keystore with password and checks
+// that it matches the public key of the keystore.
+func (ks *Keystore) Decrypt(password string) (PrivKey, error) {
+	if ks.Version != KeystoreVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", errInvalidKeystore, ks.Version)
+	}
+	secret, err := ks.Crypto.decrypt(password)
+	if err != nil {
+		return nil, err
+	}
+	privKey, err := ParsePrivKey(secret)
+	wipeBytes(secret)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidKeystore, err)
+	}
+	if len(ks.PubKey) > 0 {
+		pubKey, err := privKey.TryPubKey()
+		if err != nil {
+			privKey.Wipe()
+			return nil, err
+		}
+		if !bytes.Equal(pubKey.Bytes(), ks.PubKey) {
+			privKey.Wipe()
+			return nil, fmt.Errorf("%w: public key does not match the private key", errInvalidKeystore)
+		}
+	}
+	return privKey, nil
+}
+
+// newKeystore encrypts privKey with keys derived at the given cost and salts
+// read from rand.
+func newKeystore(rand io.Reader, privKey PrivKey, password string, params KeystoreParams, cost int) (*Keystore, error) {
+	if err := checkPrivKey(privKey); err != nil {
+		return nil, err
+	}
+	pubKey, err := privKey.TryPubKey()
+	if err != nil {
+		return nil, err
+	}
+	if params.Path != "" {
+		if _, err := ParseDerivationPath(params.Path); err != nil {
+			return nil, err
+		}
+	}
+
+	random := make([]byte, keystoreSaltSize+aes.BlockSize+16)
+	if _, err := io.ReadFull(rand, random); err != nil {
+		return nil, err
+	}
+	salt, iv, id := random[:keystoreSaltSize], random[keystoreSaltSize:keystoreSaltSize+aes.BlockSize], random[keystoreSaltSize+aes.BlockSize:]
+
+	kdf := KeystoreModule{Params: keystoreParams{DKLen: keystoreKeySize, Salt: salt}}
+	switch params.KDF {
+	case KeystoreScrypt, "":
+		kdf.Function = string(KeystoreScrypt)
+		kdf.Params.N, kdf.Params.R, kdf.Params.P = cost, 8, 1
+	case KeystorePBKDF2:
+		kdf.Function = string(KeystorePBKDF2)
+		kdf.Params.C, kdf.Params.PRF = cost, keystorePRF
+	default:
+		return nil, fmt.Errorf("%w: unsupported kdf %q", errInvalidKeystore, params.KDF)
+	}
+	c := KeystoreCrypto{
+		KDF:      kdf,
+		Checksum: KeystoreModule{Function: keystoreChecksum},
+		Cipher:   KeystoreModule{Function: keystoreCipher, Params: keystoreParams{IV: iv}},
+	}
+	if err := c.encrypt(password, privKey); err != nil {
+		return nil, err
+	}
+	return &Keystore{
+		Crypto:      c,
+		Description: params.Description,
+		PubKey:      pubKey.Bytes(),
+		Path:        params.Path,
+		UUID:        formatUUID(id),
+		Version:     KeystoreVersion,
+	}, nil
+}
+
+// newQuorumKeysKeystore is newKeystore for the private key share of keys, which
+// also records the threshold public key.
+func newQuorumKeysKeystore(rand io.Reader, keys crypto.QuorumKeys, password string, params KeystoreParams, cost int) (*Keystore, error) {
+	if keys.PrivKey == nil || keys.ThresholdPublicKey == nil {
+		return nil, fmt.Errorf("%w: missing quorum keys", errInvalidKeystore)
+	}
+	ks, err := newKeystore(rand, keys.PrivKey.Bytes(), password, params, cost)
+	if err != nil {
+		return nil, err
+	}
+	if keys.PubKey != nil && !bytes.Equal(keys.PubKey.Bytes(), ks.PubKey) {
+		return nil, fmt.Errorf("%w: public key does not match the private key", errInvalidKeystore)
+	}
+	ks.ThresholdPublicKey = keys.ThresholdPublicKey.Bytes()
+	return ks, nil
+}
+
+// encrypt sets the messages of the cipher and checksum modules to the
+// encryption of secret.
+func (c *KeystoreCrypto) encrypt(password string, secret []byte) error {
+	key, err := c.deriveKey(password)
+	if err != nil {
+		return err
+	}
+	defer wipeBytes(key)
+	c.Cipher.Message, err = c.xor(key, secret)
+	if err != nil {
+		return err
+	}
+	c.Checksum.Message = c.checksum(key)
+	return nil
+}
+
+// decrypt checks the checksum of the modules with password and returns the
+// secret.
+func (c *KeystoreCrypto) decrypt(password string) ([]byte, error) {
+	if c.Checksum.Function != keystoreChecksum {
+		return nil, fmt.Errorf("%w: unsupported checksum %q", errInvalidKeystore, c.Checksum.Function)
+	}
+	key, err := c.deriveKey(password)
+	if err != nil {
+		return nil, err
+	}
+	defer wipeBytes(key)
+	if subtle.ConstantTimeCompare(c.checksum(key), c.Checksum.Message) != 1 {
+		return nil, ErrKeystorePassword
+	}
+	return c.xor(key, c.Cipher.Message)
+}
+
+// deriveKey derives the decryption key from password with the KDF module.
+func (c *KeystoreCrypto) deriveKey(password string) ([]byte, error) {
+	p := c.KDF.Params
+	if p.DKLen != keystoreKeySize {
+		return nil, fmt.Errorf("%w: dklen %d, expected %d", errInvalidKeystore, p.DKLen, keystoreKeySize)
+	}
+	pw := processPassword(password)
+	defer wipeBytes(pw)
+	switch KeystoreKDF(c.KDF.Function) {
+	case KeystoreScrypt:
+		if p.N <= 1 || p.N > keystoreMaxCost || p.N&(p.N-1) != 0 || p.R <= 0 || p.P <= 0 ||
+			p.R > keystoreMaxScryptCost/p.N || p.P > keystoreMaxScryptCost/(p.N*p.R) {
+			return nil, fmt.Errorf("%w: scrypt parameters n=%d r=%d p=%d", errInvalidKeystore, p.N, p.R, p.P)
+		}
+		return scrypt.Key(pw, p.Salt, p.N, p.R, p.P, p.DKLen)
+	case KeystorePBKDF2:
+		if p.PRF != keystorePRF {
+			return nil, fmt.Errorf("%w: unsupported prf %q", errInvalidKeystore, p.PRF)
+		}
+		if p.C <= 0 || p.C > keystoreMaxCost {
+			return nil, fmt.Errorf("%w: pbkdf2 iterations %d", errInvalidKeystore, p.C)
+		}
+		return pbkdf2.Key(pw, p.Salt, p.C, p.DKLen, sha256.New), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported kdf %q", errInvalidKeystore, c.KDF.Function)
+	}
+}
+
+// checksum returns the checksum of the cipher message under the second half of
+// the decryption key.
+func (c *KeystoreCrypto) checksum(key []byte) []byte {
+	h := sha256.New()
+	h.Write(key[16:32])
+	h.Write(c.Cipher.Message)
+	return h.Sum(nil)
+}
+
+// xor encrypts or decrypts msg with the first half of the decryption key.
+func (c *KeystoreCrypto) xor(key, msg []byte) ([]byte, error) {
+	if c.Cipher.Function != keystoreCipher {
+		return nil, fmt.Errorf("%w: unsupported cipher %q", errInvalidKeystore, c.Cipher.Function)
+	}
+	if len(c.Cipher.Params.IV) != aes.BlockSize {
+		return nil, fmt.Errorf("%w: iv size %d", errInvalidKeystore, len(c.Cipher.Params.IV))
+	}
+	block, err := aes.NewCipher(key[:16])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(msg))
+	cipher.NewCTR(block, c.Cipher.Params.IV).XORKeyStream(out, msg)
+	return out, nil
+}
+
+// processPassword normalizes password to NFKD and strips the control codes,
+// as required by EIP-2335.
+func processPassword(password string) []byte {
+	return []byte(strings.Map(func(r rune) rune {
+		if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+			return -1
+		}
+		return r
+	}, norm.NFKD.String(password)))
+}
+
+// formatUUID formats 16 random bytes as a version 4 UUID.
+func formatUUID(b []byte) string {
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
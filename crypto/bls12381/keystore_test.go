@@ -0,0 +1,224 @@
+package bls12381
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+const (
+	// keystoreTestPassword and keystoreTestSecret are those of the test
+	// vectors of EIP-2335.
+	keystoreTestPassword = "\U0001d531\U0001d522\U0001d530\U0001d531\U0001d52d\U0001d51e\U0001d530\U0001d530\U0001d534\U0001d52c\U0001d52f\U0001d521\U0001f511"
+	keystoreTestSecret   = "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"
+	// keystoreTestCost keeps the key derivation of tests fast.
+	keystoreTestCost = 1 << 4
+)
+
+func TestKeystoreKnownAnswers(t *testing.T) {
+	testCases := map[string]string{
+		"scrypt": `{
+			"crypto": {
+				"kdf": {
+					"function": "scrypt",
+					"params": {"dklen": 32, "n": 262144, "p": 1, "r": 8, "salt": "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"},
+					"message": ""
+				},
+				"checksum": {
+					"function": "sha256",
+					"params": {},
+					"message": "d2217fe5f3e9a1e34581ef8a78f7c9928e436d36dacc5e846690a5581e8ea484"
+				},
+				"cipher": {
+					"function": "aes-128-ctr",
+					"params": {"iv": "264daa3f303d7259501c93d997d84fe6"},
+					"message": "06ae90d55fe0a6e9c5c3bc5b170827b2e5cce3929ed3f116c2811e6366dfe20f"
+				}
+			},
+			"description": "This is a test keystore that uses scrypt to secure the secret.",
+			"pubkey": "9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07",
+			"path": "m/12381/60/3141592653/589793238",
+			"uuid": "1d85ae20-35c5-4611-98e8-aa14a633906f",
+			"version": 4
+		}`,
+		"pbkdf2": `{
+			"crypto": {
+				"kdf": {
+					"function": "pbkdf2",
+					"params": {"dklen": 32, "c": 262144, "prf": "hmac-sha256", "salt": "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"},
+					"message": ""
+				},
+				"checksum": {
+					"function": "sha256",
+					"params": {},
+					"message": "8a9f5d9912ed7e75ea794bc5a89bca5f193721d30868ade6f73043c6ea6febf1"
+				},
+				"cipher": {
+					"function": "aes-128-ctr",
+					"params": {"iv": "264daa3f303d7259501c93d997d84fe6"},
+					"message": "cee03fde2af33149775b7223e7845e4fb2c8ae1792e5f99fe9ecf474cc8c16ad"
+				}
+			},
+			"description": "This is a test keystore that uses PBKDF2 to secure the secret.",
+			"pubkey": "9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07",
+			"path": "m/12381/60/0/0",
+			"uuid": "64625def-3331-4eea-ab6f-782f3ed16a83",
+			"version": 4
+		}`,
+	}
+	for name, doc := range testCases {
+		doc := doc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			privKey, err := DecryptKeystore([]byte(doc), keystoreTestPassword)
+			require.NoError(t, err)
+			assert.Equal(t, PrivKey(mustDecodeHex(t, keystoreTestSecret)), privKey)
+		})
+	}
+}
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	privKey := GenPrivKey()
+	for _, kdf := range []KeystoreKDF{KeystoreScrypt, KeystorePBKDF2} {
+		kdf := kdf
+		t.Run(string(kdf), func(t *testing.T) {
+			params := KeystoreParams{KDF: kdf, Description: "operator key", Path: "m/12381/3600/0/0"}
+			ks, err := newKeystore(bytes.NewReader(bytes.Repeat([]byte{7}, 64)), privKey, "secret\n", params, keystoreTestCost)
+			require.NoError(t, err)
+			assert.Equal(t, KeystoreVersion, ks.Version)
+			assert.Equal(t, "07070707-0707-4707-8707-070707070707", ks.UUID)
+			assert.Equal(t, privKey.PubKey().Bytes(), []byte(ks.PubKey))
+			assert.NotContains(t, string(mustMarshal(t, ks)), hex.EncodeToString(privKey))
+
+			data := mustMarshal(t, ks)
+			decrypted, err := DecryptKeystore(data, "secret")
+			require.NoError(t, err)
+			assert.Equal(t, privKey, decrypted)
+
+			_, err = DecryptKeystore(data, "wrong")
+			assert.ErrorIs(t, err, ErrKeystorePassword)
+		})
+	}
+}
+
+func TestKeystorePasswordNormalization(t *testing.T) {
+	privKey := GenPrivKey()
+	ks, err := newKeystore(bytes.NewReader(make([]byte, 64)), privKey, keystoreTestPassword, KeystoreParams{}, keystoreTestCost)
+	require.NoError(t, err)
+	data := mustMarshal(t, ks)
+
+	// NFKD maps the letters to ASCII and control codes are stripped
+	for _, password := range []string{"testpassword\U0001f511", "test\x7fpass\u0085word\t\U0001f511"} {
+		decrypted, err := DecryptKeystore(data, password)
+		require.NoError(t, err, "%q", password)
+		assert.Equal(t, privKey, decrypted)
+	}
+	_, err = DecryptKeystore(data, "testpassword")
+	assert.ErrorIs(t, err, ErrKeystorePassword)
+}
+
+func TestEncryptKeystore(t *testing.T) {
+	privKey := GenPrivKey()
+	_, err := EncryptKeystore(privKey, "secret", KeystoreParams{KDF: "argon2"})
+	assert.ErrorIs(t, err, errInvalidKeystore)
+	_, err = EncryptKeystore(privKey, "secret", KeystoreParams{Path: "m/44/5/0"})
+	assert.ErrorIs(t, err, errInvalidDerivationPath)
+	_, err = EncryptKeystore(make(PrivKey, PrivateKeySize), "secret", KeystoreParams{})
+	assert.ErrorIs(t, err, ErrPrivKeyInvalid)
+
+	data, err := EncryptKeystore(privKey, "secret", KeystoreParams{KDF: KeystorePBKDF2})
+	require.NoError(t, err)
+	var ks Keystore
+	require.NoError(t, json.Unmarshal(data, &ks))
+	assert.Equal(t, keystoreCost, ks.Crypto.KDF.Params.C)
+	decrypted, err := ks.Decrypt("secret")
+	require.NoError(t, err)
+	assert.Equal(t, privKey, decrypted)
+}
+
+func TestDecryptKeystoreInvalid(t *testing.T) {
+	privKey := GenPrivKey()
+	testCases := map[string]func(ks *Keystore){
+		"version": func(ks *Keystore) { ks.Version = 3 },
+		"kdf":     func(ks *Keystore) { ks.Crypto.KDF.Function = "argon2" },
+		"dklen":   func(ks *Keystore) { ks.Crypto.KDF.Params.DKLen = 16 },
+		"cost":    func(ks *Keystore) { ks.Crypto.KDF.Params.N = keystoreMaxCost * 2 },
+		"n":       func(ks *Keystore) { ks.Crypto.KDF.Params.N = 1000 },
+		"r":       func(ks *Keystore) { ks.Crypto.KDF.Params.R = 1 << 30 },
+		"p":       func(ks *Keystore) { ks.Crypto.KDF.Params.P = keystoreMaxScryptCost },
+		"n*r*p": func(ks *Keystore) {
+			ks.Crypto.KDF.Params.N = keystoreMaxCost
+			ks.Crypto.KDF.Params.R = 8
+			ks.Crypto.KDF.Params.P = 2
+		},
+		"checksum": func(ks *Keystore) { ks.Crypto.Checksum.Function = "sha512" },
+		"cipher":   func(ks *Keystore) { ks.Crypto.Cipher.Function = "aes-256-gcm" },
+		"iv":       func(ks *Keystore) { ks.Crypto.Cipher.Params.IV = ks.Crypto.Cipher.Params.IV[1:] },
+		"pubkey":   func(ks *Keystore) { ks.PubKey = GenPrivKey().PubKey().Bytes() },
+	}
+	for name, modify := range testCases {
+		modify := modify
+		t.Run(name, func(t *testing.T) {
+			ks, err := newKeystore(bytes.NewReader(make([]byte, 64)), privKey, "secret", KeystoreParams{}, keystoreTestCost)
+			require.NoError(t, err)
+			modify(ks)
+			_, err = DecryptKeystore(mustMarshal(t, ks), "secret")
+			assert.ErrorIs(t, err, errInvalidKeystore)
+		})
+	}
+
+	_, err := DecryptKeystore([]byte(`{"pubkey": "zz"}`), "secret")
+	assert.ErrorIs(t, err, errInvalidKeystore)
+}
+
+func TestQuorumKeysKeystore(t *testing.T) {
+	privKey := GenPrivKey()
+	keys := crypto.QuorumKeys{
+		PrivKey:            privKey,
+		PubKey:             privKey.PubKey(),
+		ThresholdPublicKey: GenPrivKey().PubKey(),
+	}
+	ks, err := newQuorumKeysKeystore(bytes.NewReader(make([]byte, 64)), keys, "secret", KeystoreParams{}, keystoreTestCost)
+	require.NoError(t, err)
+	data := mustMarshal(t, ks)
+	decrypted, err := DecryptQuorumKeysKeystore(data, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, keys, decrypted)
+
+	// the key share can be read as a plain keystore too
+	decryptedKey, err := DecryptKeystore(data, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, privKey, decryptedKey)
+
+	// a plain keystore has no threshold public key
+	ks, err = newKeystore(bytes.NewReader(make([]byte, 64)), privKey, "secret", KeystoreParams{}, keystoreTestCost)
+	require.NoError(t, err)
+	_, err = DecryptQuorumKeysKeystore(mustMarshal(t, ks), "secret")
+	assert.ErrorIs(t, err, errInvalidKeystore)
+
+	// nor can the public key share be left out
+	ks, err = newQuorumKeysKeystore(bytes.NewReader(make([]byte, 64)), keys, "secret", KeystoreParams{}, keystoreTestCost)
+	require.NoError(t, err)
+	ks.PubKey = nil
+	_, err = DecryptQuorumKeysKeystore(mustMarshal(t, ks), "secret")
+	assert.ErrorIs(t, err, errInvalidKeystore)
+
+	keys.PubKey = GenPrivKey().PubKey()
+	_, err = EncryptQuorumKeysKeystore(keys, "secret", KeystoreParams{})
+	assert.ErrorIs(t, err, errInvalidKeystore)
+	_, err = EncryptQuorumKeysKeystore(crypto.QuorumKeys{PrivKey: privKey}, "secret", KeystoreParams{})
+	assert.ErrorIs(t, err, errInvalidKeystore)
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
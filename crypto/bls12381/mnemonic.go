@@ -0,0 +1,181 @@
+package bls12381
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed" // for the BIP-39 wordlist
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+const (
+	// MnemonicDefaultEntropyBits is the entropy of the mnemonics NewMnemonic
+	// generates by default, which are 24 words long.
+	MnemonicDefaultEntropyBits = 256
+
+	mnemonicMinEntropyBits = 128
+	mnemonicMaxEntropyBits = 256
+	// mnemonicWordBits is the number of bits every word encodes.
+	mnemonicWordBits = 11
+	// mnemonicSeedIterations is the PBKDF2 iteration count of BIP-39 seeds.
+	mnemonicSeedIterations = 2048
+	// MnemonicSeedSize is the size of the seeds derived from mnemonics.
+	MnemonicSeedSize = 64
+)
+
+var (
+	// ErrInvalidMnemonic is returned for phrases that are not BIP-39 mnemonics
+	// of the English wordlist.
+	ErrInvalidMnemonic = errors.New("invalid mnemonic")
+	// ErrMnemonicChecksum is returned for mnemonics with a wrong checksum,
+	// usually because a word was mistyped or left out.
+	ErrMnemonicChecksum = fmt.Errorf("%w: checksum mismatch", ErrInvalidMnemonic)
+)
+
+// bip39English is the English wordlist of BIP-39, one word per line.
+//
+//go:embed bip39_english.txt
+var bip39English string
+
+var (
+	bip39WordsOnce sync.Once
+	bip39Words     []string
+	bip39Indices   map[string]int
+)
+
+// mnemonicWords returns the English wordlist and the index of every word.
+func mnemonicWords() ([]string, map[string]int) {
+	bip39WordsOnce.Do(func() {
+		bip39Words = strings.Fields(bip39English)
+		bip39Indices = make(map[string]int, len(bip39Words))
+		for i, word := range bip39Words {
+			bip39Indices[word] = i
+		}
+	})
+	return bip39Words, bip39Indices
+}
+
+// NewMnemonic generates a BIP-39 mnemonic that encodes entropyBits bits of
+// entropy from crypto.CRandBytes and a checksum. entropyBits is a multiple of
+// 32 between 128 and 256, giving 12 to 24 words.
+func NewMnemonic(entropyBits int) (string, error) {
+	if err := validateEntropyBits(entropyBits); err != nil {
+		return "", err
+	}
+	entropy := crypto.CRandBytes(entropyBits / 8)
+	defer wipeBytes(entropy)
+	return EntropyToMnemonic(entropy)
+}
+
+// EntropyToMnemonic encodes entropy as a BIP-39 mnemonic.
+func EntropyToMnemonic(entropy []byte) (string, error) {
+	entropyBits := len(entropy) * 8
+	if err := validateEntropyBits(entropyBits); err != nil {
+		return "", err
+	}
+	words, _ := mnemonicWords()
+	checksumBits := entropyBits / 32
+	checksum := sha256.Sum256(entropy)
+
+	// the entropy followed by the first bits of its hash, 11 bits per word
+	b := new(big.Int).SetBytes(entropy)
+	defer wipeScalar(b)
+	b.Lsh(b, uint(checksumBits))
+	b.Or(b, big.NewInt(int64(checksum[0]>>(8-checksumBits))))
+
+	phrase := make([]string, (entropyBits+checksumBits)/mnemonicWordBits)
+	mask := big.NewInt(1<<mnemonicWordBits - 1)
+	index := new(big.Int)
+	for i := len(phrase) - 1; i >= 0; i-- {
+		index.And(b, mask)
+		phrase[i] = words[index.Int64()]
+		b.Rsh(b, mnemonicWordBits)
+	}
+	return strings.Join(phrase, " "), nil
+}
+
+// MnemonicToEntropy decodes the entropy of a BIP-39 mnemonic and checks its
+// checksum. Words are separated by any whitespace.
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	phrase := strings.Fields(norm.NFKD.String(mnemonic))
+	totalBits := len(phrase) * mnemonicWordBits
+	checksumBits := totalBits / 33
+	entropyBits := totalBits - checksumBits
+	if len(phrase)%3 != 0 || validateEntropyBits(entropyBits) != nil {
+		return nil, fmt.Errorf("%w: %d words, expected 12, 15, 18, 21 or 24", ErrInvalidMnemonic, len(phrase))
+	}
+	_, indices := mnemonicWords()
+	b := new(big.Int)
+	defer wipeScalar(b)
+	for i, word := range phrase {
+		index, ok := indices[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: word %d is not in the wordlist", ErrInvalidMnemonic, i+1)
+		}
+		b.Lsh(b, mnemonicWordBits)
+		b.Or(b, big.NewInt(int64(index)))
+	}
+
+	checksum := new(big.Int).And(b, big.NewInt(1<<checksumBits-1)).Uint64()
+	b.Rsh(b, uint(checksumBits))
+	entropy := b.FillBytes(make([]byte, entropyBits/8))
+	hash := sha256.Sum256(entropy)
+	if uint64(hash[0]>>(8-checksumBits)) != checksum {
+		wipeBytes(entropy)
+		return nil, ErrMnemonicChecksum
+	}
+	return entropy, nil
+}
+
+// ValidateMnemonic checks that mnemonic is a BIP-39 mnemonic of the English
+// wordlist with a valid checksum.
+func ValidateMnemonic(mnemonic string) error {
+	entropy, err := MnemonicToEntropy(mnemonic)
+	if err != nil {
+		return err
+	}
+	wipeBytes(entropy)
+	return nil
+}
+
+// MnemonicToSeed validates mnemonic and derives the BIP-39 seed of it with an
+// optional passphrase. The seed can be given to DeriveMasterSK and
+// DeriveSKFromPath.
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	phrase := []byte(strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " "))
+	defer wipeBytes(phrase)
+	salt := []byte("mnemonic" + norm.NFKD.String(passphrase))
+	defer wipeBytes(salt)
+	return pbkdf2.Key(phrase, salt, mnemonicSeedIterations, MnemonicSeedSize, sha512.New), nil
+}
+
+// PrivKeyFromMnemonic restores the private key at the EIP-2334 path of the
+// seed of mnemonic and passphrase, see MnemonicToSeed and DeriveSKFromPath.
+func PrivKeyFromMnemonic(mnemonic, passphrase, path string) (PrivKey, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer wipeBytes(seed)
+	return DeriveSKFromPath(seed, path)
+}
+
+// validateEntropyBits checks the entropy size of a mnemonic.
+func validateEntropyBits(bits int) error {
+	if bits < mnemonicMinEntropyBits || bits > mnemonicMaxEntropyBits || bits%32 != 0 {
+		return fmt.Errorf("%w: entropy of %d bits, expected a multiple of 32 between %d and %d",
+			ErrInvalidMnemonic, bits, mnemonicMinEntropyBits, mnemonicMaxEntropyBits)
+	}
+	return nil
+}
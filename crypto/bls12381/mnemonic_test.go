@@ -0,0 +1,127 @@
+package bls12381
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bip39Vectors is the format of testdata/bip39/vectors.json.
+type bip39Vectors struct {
+	Passphrase string `json:"passphrase"`
+	// English holds entropy, mnemonic and seed triples.
+	English [][3]string `json:"english"`
+}
+
+func TestMnemonicWordlist(t *testing.T) {
+	// checksum of the english.txt of the BIP-39 repository
+	assert.Equal(t, uint32(0xc1dbd296), crc32.ChecksumIEEE([]byte(bip39English)))
+	words, indices := mnemonicWords()
+	assert.Len(t, words, 1<<mnemonicWordBits)
+	assert.Len(t, indices, 1<<mnemonicWordBits)
+}
+
+func TestMnemonicKnownAnswers(t *testing.T) {
+	data, err := os.ReadFile("testdata/bip39/vectors.json")
+	require.NoError(t, err)
+	var vectors bip39Vectors
+	require.NoError(t, json.Unmarshal(data, &vectors))
+	require.NotEmpty(t, vectors.English)
+
+	for _, v := range vectors.English {
+		entropy, mnemonic, seed := v[0], v[1], v[2]
+		t.Run(entropy, func(t *testing.T) {
+			got, err := EntropyToMnemonic(mustDecodeHex(t, entropy))
+			require.NoError(t, err)
+			assert.Equal(t, mnemonic, got)
+
+			gotEntropy, err := MnemonicToEntropy(mnemonic)
+			require.NoError(t, err)
+			assert.Equal(t, entropy, hex.EncodeToString(gotEntropy))
+
+			gotSeed, err := MnemonicToSeed(mnemonic, vectors.Passphrase)
+			require.NoError(t, err)
+			assert.Equal(t, seed, hex.EncodeToString(gotSeed))
+		})
+	}
+}
+
+// TestPrivKeyFromMnemonic checks that the seed of the first BIP-39 test vector
+// is the seed of the first EIP-2333 one.
+func TestPrivKeyFromMnemonic(t *testing.T) {
+	mnemonic := strings.Repeat("abandon ", 11) + "about"
+	sk, err := PrivKeyFromMnemonic(mnemonic, "TREZOR", "m")
+	require.NoError(t, err)
+	assert.Equal(t, "6083874454709270928345386274498605044986640685124978867557563392430687146096",
+		new(big.Int).SetBytes(sk).String())
+
+	sk, err = PrivKeyFromMnemonic(mnemonic, "TREZOR", "m/0")
+	assert.ErrorIs(t, err, errInvalidDerivationPath)
+	assert.Nil(t, sk)
+
+	// whitespace does not matter, the passphrase does
+	seed, err := MnemonicToSeed(mnemonic, "")
+	require.NoError(t, err)
+	spaced, err := MnemonicToSeed(" "+strings.ReplaceAll(mnemonic, " ", "\t ")+"\n", "")
+	require.NoError(t, err)
+	assert.Equal(t, seed, spaced)
+	withPassphrase, err := MnemonicToSeed(mnemonic, "TREZOR")
+	require.NoError(t, err)
+	assert.NotEqual(t, seed, withPassphrase)
+}
+
+func TestNewMnemonic(t *testing.T) {
+	for bits := mnemonicMinEntropyBits; bits <= mnemonicMaxEntropyBits; bits += 32 {
+		mnemonic, err := NewMnemonic(bits)
+		require.NoError(t, err)
+		assert.Len(t, strings.Fields(mnemonic), bits*33/32/mnemonicWordBits)
+		assert.NoError(t, ValidateMnemonic(mnemonic))
+
+		other, err := NewMnemonic(bits)
+		require.NoError(t, err)
+		assert.NotEqual(t, mnemonic, other)
+	}
+	mnemonic, err := NewMnemonic(MnemonicDefaultEntropyBits)
+	require.NoError(t, err)
+	assert.Len(t, strings.Fields(mnemonic), 24)
+
+	for _, bits := range []int{0, 96, 136, 288} {
+		_, err := NewMnemonic(bits)
+		assert.ErrorIs(t, err, ErrInvalidMnemonic, bits)
+		_, err = EntropyToMnemonic(make([]byte, bits/8))
+		assert.ErrorIs(t, err, ErrInvalidMnemonic, bits)
+	}
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	valid := "legal winner thank year wave sausage worth useful legal winner thank yellow"
+	require.NoError(t, ValidateMnemonic(valid))
+
+	testCases := []struct {
+		mnemonic string
+		err      error
+	}{
+		{"", ErrInvalidMnemonic},
+		{strings.TrimSuffix(valid, " yellow"), ErrInvalidMnemonic},
+		{valid + " yellow", ErrInvalidMnemonic},
+		{strings.Replace(valid, "wave", "wavy", 1), ErrInvalidMnemonic},
+		{strings.Replace(valid, "legal", "LEGAL", 1), ErrInvalidMnemonic},
+		{strings.Replace(valid, "yellow", "year", 1), ErrMnemonicChecksum},
+		{strings.Replace(valid, "thank year", "year thank", 1), ErrMnemonicChecksum},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			assert.ErrorIs(t, ValidateMnemonic(tc.mnemonic), tc.err)
+			_, err := MnemonicToSeed(tc.mnemonic, "")
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package bls12381
+
+import (
+	"math/big"
+)
+
+// ParsePrivKey decodes a private key, checking that it is a non-zero scalar
+// below the group order. The returned key does not share memory with b.
+func ParsePrivKey(b []byte) (PrivKey, error) {
+	if err := checkPrivKey(b); err != nil {
+		return nil, err
+	}
+	return append(PrivKey(nil), b...), nil
+}
+
+// checkPrivKey checks that b is a private key without leaving copies of it.
+func checkPrivKey(b []byte) error {
+	if len(b) != PrivateKeySize {
+		return errInvalidPrivateKeySize(len(b))
+	}
+	s := new(big.Int).SetBytes(b)
+	defer wipeScalar(s)
+	if s.Sign() == 0 || s.Cmp(curveOrder) >= 0 {
+		return ErrPrivKeyInvalid
+	}
+	return nil
+}
+
+// ParsePubKey decodes a public key, checking that it is the encoding of a
+// point of the G1 subgroup other than the point at infinity, see
+// PubKey.Validate. The returned key does not share memory with b.
+func ParsePubKey(b []byte) (PubKey, error) {
+	pubKey := append(PubKey(nil), b...)
+	if err := pubKey.Validate(); err != nil {
+		return nil, err
+	}
+	return pubKey, nil
+}
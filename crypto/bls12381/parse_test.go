@@ -0,0 +1,124 @@
+package bls12381
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+	"github.com/dashpay/tenderdash/internal/jsontypes"
+)
+
+func TestParsePrivKey(t *testing.T) {
+	privKey := GenPrivKey()
+	parsed, err := ParsePrivKey(privKey)
+	require.NoError(t, err)
+	assert.Equal(t, privKey, parsed)
+
+	testCases := []struct {
+		name string
+		b    []byte
+		err  error
+	}{
+		{name: "empty", b: nil, err: ErrPrivKeyInvalidSize},
+		{name: "short", b: privKey[1:], err: ErrPrivKeyInvalidSize},
+		{name: "zero", b: make([]byte, PrivateKeySize), err: ErrPrivKeyInvalid},
+		{name: "group order", b: scalarToBytes(curveOrder), err: ErrPrivKeyInvalid},
+		{name: "above group order", b: bytes.Repeat([]byte{0xff}, PrivateKeySize), err: ErrPrivKeyInvalid},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParsePrivKey(tc.b)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestParsePubKey(t *testing.T) {
+	pubKey := GenPrivKey().PubKey().(PubKey)
+	parsed, err := ParsePubKey(pubKey)
+	require.NoError(t, err)
+	assert.Equal(t, pubKey, parsed)
+
+	testCases := []struct {
+		name string
+		b    []byte
+		err  error
+	}{
+		{name: "empty", b: nil, err: ErrPubKeyInvalidSize},
+		{name: "short", b: pubKey[1:], err: ErrPubKeyInvalidSize},
+		{name: "all zeros", b: emptyPubKeyVal, err: ErrPubKeyIsEmpty},
+		{name: "infinity", b: append([]byte{0xc0}, make([]byte, PubKeySize-1)...), err: ErrPubKeyInfinity},
+		{name: "infinity not all zeros", b: mustHexToBytes("c00000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000000"), err: ErrPubKeyInvalidEncoding},
+		{name: "compression flag unset", b: mustHexToBytes("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaaa"), err: ErrPubKeyInvalidEncoding},
+		{name: "x equal to p", b: mustHexToBytes("9a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab"), err: ErrPubKeyInvalidEncoding},
+		{name: "x not on curve", b: mustHexToBytes("9a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaaa"), err: ErrPubKeyNotOnCurve},
+		{name: "not in subgroup", b: mustHexToBytes("800000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000004"), err: ErrPubKeyNotInSubgroup},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParsePubKey(tc.b)
+			assert.ErrorIs(t, err, tc.err)
+			if len(tc.b) == PubKeySize && tc.err != ErrPubKeyIsEmpty {
+				assert.ErrorIs(t, err, ErrPubKeyInvalid)
+			}
+		})
+	}
+}
+
+func TestPubKeyJSONValidation(t *testing.T) {
+	pubKey := GenPrivKey().PubKey().(PubKey)
+	data, err := jsontypes.Marshal(pubKey)
+	require.NoError(t, err)
+	var decoded PubKey
+	require.NoError(t, jsontypes.Unmarshal(data, &decoded))
+	assert.Equal(t, pubKey, decoded)
+
+	require.NoError(t, json.Unmarshal([]byte("null"), &decoded))
+	assert.Nil(t, decoded)
+
+	notInSubgroup, err := json.Marshal(mustHexToBytes("800000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000004"))
+	require.NoError(t, err)
+	assert.ErrorIs(t, json.Unmarshal(notInSubgroup, &decoded), ErrPubKeyNotInSubgroup)
+
+	var quorumKeys crypto.QuorumKeys
+	data, err = json.Marshal(crypto.QuorumKeys{
+		PrivKey:            GenPrivKey(),
+		PubKey:             pubKey,
+		ThresholdPublicKey: PubKey(append([]byte{0xc0}, make([]byte, PubKeySize-1)...)),
+	})
+	require.NoError(t, err)
+	assert.ErrorIs(t, json.Unmarshal(data, &quorumKeys), ErrPubKeyInfinity)
+}
+
+func TestTryPubKeyAndAddress(t *testing.T) {
+	privKey := GenPrivKey()
+	pubKey, err := privKey.TryPubKey()
+	require.NoError(t, err)
+	assert.Equal(t, privKey.PubKey(), pubKey)
+	address, err := pubKey.TryAddress()
+	require.NoError(t, err)
+	assert.Equal(t, pubKey.Address(), address)
+
+	_, err = privKey[1:].TryPubKey()
+	assert.ErrorIs(t, err, ErrPrivKeyInvalidSize)
+	assert.Panics(t, func() { privKey[1:].PubKey() })
+	_, err = pubKey[1:].TryAddress()
+	assert.ErrorIs(t, err, ErrPubKeyInvalidSize)
+	assert.Panics(t, func() { pubKey[1:].Address() })
+}
+
+func TestSignWrongSizeKey(t *testing.T) {
+	privKey := GenPrivKey()[1:]
+	assert.NotPanics(t, func() {
+		_, err := privKey.Sign([]byte("message"))
+		assert.ErrorIs(t, err, ErrPrivKeyInvalidSize)
+		_, err = privKey.SignDigest(crypto.CRandBytes(32))
+		assert.ErrorIs(t, err, ErrPrivKeyInvalidSize)
+	})
+}
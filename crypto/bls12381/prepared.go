@@ -0,0 +1,139 @@
+package bls12381
+
+import (
+	"container/list"
+	"sync"
+)
+
+// pubKeyCacheSize bounds the number of public keys PubKey.VerifySignature keeps
+// decoded. It comfortably holds the validator sets a node verifies votes of.
+// One-off keys, such as aggregates, are verified without the cache, so that
+// they do not evict the keys of validators.
+const pubKeyCacheSize = 1024
+
+// preparedPubKeys caches the decoded public keys that signatures are verified
+// against, as validator sets rarely change.
+var preparedPubKeys = newPubKeyCache(pubKeyCacheSize)
+
+// PreparedPubKey is a public key that is validated and decoded once, so that
+// signatures by it are verified without decoding it again. Unlike the shared
+// cache of PubKey.VerifySignature, it is never evicted. It is safe for
+// concurrent use.
+type PreparedPubKey struct {
+	pubKey   PubKey
+	prepared preparedPubKey
+}
+
+// Prepare validates the public key and decodes it for repeated verifications.
+// The key is decoded once, which also checks that it is in the G1 subgroup.
+func (pubKey PubKey) Prepare() (*PreparedPubKey, error) {
+	if err := pubKey.validateEncoding(); err != nil {
+		return nil, err
+	}
+	prepared, err := defaultBackend.preparePubKey(pubKey)
+	if err != nil {
+		return nil, ErrPubKeyNotInSubgroup
+	}
+	return &PreparedPubKey{
+		pubKey:   append(PubKey(nil), pubKey...),
+		prepared: prepared,
+	}, nil
+}
+
+// PubKey returns the public key that was prepared.
+func (p *PreparedPubKey) PubKey() PubKey {
+	return p.pubKey
+}
+
+// VerifySignature is PubKey.VerifySignature.
+func (p *PreparedPubKey) VerifySignature(msg []byte, sig []byte) bool {
+	return p.Verify(SchemeBasic, msg, sig)
+}
+
+// VerifySignatureDigest is PubKey.VerifySignatureDigest.
+func (p *PreparedPubKey) VerifySignatureDigest(hash []byte, sig []byte) bool {
+	return p.VerifySignature(hash, sig)
+}
+
+// Verify reports whether sig is a signature of msg by the public key under the
+// given scheme.
+func (p *PreparedPubKey) Verify(scheme Scheme, msg []byte, sig []byte) bool {
+	if !scheme.valid() || len(sig) != SignatureSize {
+		return false
+	}
+	return p.prepared.verify(scheme, msg, sig)
+}
+
+// pubKeyCache is a bounded cache of decoded public keys keyed by their bytes,
+// which evicts the least recently used key when full. It is safe for
+// concurrent use.
+type pubKeyCache struct {
+	mtx     sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	// lru holds the cached keys, most recently used first
+	lru *list.List
+}
+
+type pubKeyCacheEntry struct {
+	pk       string
+	prepared preparedPubKey
+}
+
+func newPubKeyCache(size int) *pubKeyCache {
+	return &pubKeyCache{
+		size:    size,
+		entries: make(map[string]*list.Element, size),
+		lru:     list.New(),
+	}
+}
+
+// get returns the decoded public key pk, decoding it if it is not cached yet.
+// Keys that fail to decode are not cached.
+func (c *pubKeyCache) get(pk []byte) (preparedPubKey, error) {
+	c.mtx.Lock()
+	if e, ok := c.entries[string(pk)]; ok {
+		c.lru.MoveToFront(e)
+		c.mtx.Unlock()
+		return e.Value.(*pubKeyCacheEntry).prepared, nil
+	}
+	c.mtx.Unlock()
+
+	// decode outside of the lock, so that concurrent misses do not wait on
+	// each other
+	prepared, err := defaultBackend.preparePubKey(pk)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if e, ok := c.entries[string(pk)]; ok {
+		c.lru.MoveToFront(e)
+		return e.Value.(*pubKeyCacheEntry).prepared, nil
+	}
+	if c.lru.Len() >= c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*pubKeyCacheEntry).pk)
+	}
+	c.entries[string(pk)] = c.lru.PushFront(&pubKeyCacheEntry{pk: string(pk), prepared: prepared})
+	return prepared, nil
+}
+
+// verify reports whether sig is a signature of msg by pk under the given
+// scheme, decoding pk through the cache.
+func (c *pubKeyCache) verify(scheme Scheme, pk, msg, sig []byte) bool {
+	prepared, err := c.get(pk)
+	if err != nil {
+		return false
+	}
+	return prepared.verify(scheme, msg, sig)
+}
+
+// len returns the number of cached keys.
+func (c *pubKeyCache) len() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.lru.Len()
+}
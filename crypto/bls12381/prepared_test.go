@@ -0,0 +1,125 @@
+package bls12381
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+func TestPreparedPubKey(t *testing.T) {
+	privKey := GenPrivKey()
+	pubKey := privKey.PubKey().(PubKey)
+	msg := crypto.CRandBytes(32)
+
+	prepared, err := pubKey.Prepare()
+	require.NoError(t, err)
+	assert.Equal(t, pubKey, prepared.PubKey())
+
+	sig, err := privKey.Sign(msg)
+	require.NoError(t, err)
+	assert.True(t, prepared.VerifySignature(msg, sig))
+	assert.True(t, prepared.VerifySignatureDigest(msg, sig))
+	assert.False(t, prepared.VerifySignature(append(msg, 1), sig))
+	assert.False(t, prepared.VerifySignature(msg, sig[1:]))
+	assert.False(t, prepared.Verify(Scheme(42), msg, sig))
+
+	for _, scheme := range []Scheme{SchemeBasic, SchemeAugmented, SchemePoP} {
+		sig, err := scheme.Sign(privKey, msg)
+		require.NoError(t, err)
+		assert.True(t, prepared.Verify(scheme, msg, sig), scheme)
+		assert.Equal(t, scheme.Verify(pubKey, msg, sig), prepared.Verify(scheme, msg, sig), scheme)
+	}
+
+	// the decoded key is used, not its bytes
+	prepared.pubKey = append(PubKey(nil), pubKey...)
+	prepared.pubKey[PubKeySize-1] ^= 1
+	assert.True(t, prepared.VerifySignature(msg, sig))
+
+	_, err = PubKey(make([]byte, PubKeySize)).Prepare()
+	assert.ErrorIs(t, err, ErrPubKeyIsEmpty)
+	_, err = pubKey[1:].Prepare()
+	assert.ErrorIs(t, err, ErrPubKeyInvalidSize)
+}
+
+func TestPubKeyCache(t *testing.T) {
+	const size = 4
+	cache := newPubKeyCache(size)
+	msg := crypto.CRandBytes(32)
+	privKeys := make([]PrivKey, size+1)
+	for i := range privKeys {
+		privKeys[i] = GenPrivKey()
+	}
+	pubKey := func(i int) []byte { return privKeys[i].PubKey().Bytes() }
+
+	for i := 0; i < size; i++ {
+		sig, err := privKeys[i].Sign(msg)
+		require.NoError(t, err)
+		assert.True(t, cache.verify(SchemeBasic, pubKey(i), msg, sig))
+		assert.False(t, cache.verify(SchemeBasic, pubKey(i), append(msg, 1), sig))
+	}
+	assert.Equal(t, size, cache.len())
+
+	// using key 0 makes key 1 the least recently used one, which is evicted
+	_, err := cache.get(pubKey(0))
+	require.NoError(t, err)
+	_, err = cache.get(pubKey(size))
+	require.NoError(t, err)
+	assert.Equal(t, size, cache.len())
+	assert.Contains(t, cache.entries, string(pubKey(0)))
+	assert.NotContains(t, cache.entries, string(pubKey(1)))
+	assert.Contains(t, cache.entries, string(pubKey(size)))
+
+	// keys that do not decode are not cached
+	_, err = cache.get(pubKey(0)[1:])
+	assert.Error(t, err)
+	assert.NotContains(t, cache.entries, string(pubKey(0)[1:]))
+	assert.Equal(t, size, cache.len())
+}
+
+func TestAggregatesAreNotCached(t *testing.T) {
+	msg := crypto.CRandBytes(32)
+	pubKeys := make([]crypto.PubKey, 3)
+	sigs := make([][]byte, len(pubKeys))
+	for i := range pubKeys {
+		privKey := GenPrivKey()
+		pubKeys[i] = privKey.PubKey()
+		sigs[i] = mustSign(t, privKey, msg)
+	}
+	aggregate, err := AggregatePubKeys(pubKeys)
+	require.NoError(t, err)
+	sig, err := AggregateSignatures(sigs)
+	require.NoError(t, err)
+
+	require.True(t, VerifyAggregateSameMessage(pubKeys, msg, sig))
+	preparedPubKeys.mtx.Lock()
+	defer preparedPubKeys.mtx.Unlock()
+	assert.NotContains(t, preparedPubKeys.entries, string(aggregate))
+}
+
+func TestPubKeyCacheConcurrency(t *testing.T) {
+	cache := newPubKeyCache(2)
+	msg := crypto.CRandBytes(32)
+	privKeys := []PrivKey{GenPrivKey(), GenPrivKey(), GenPrivKey()}
+	sigs := make([][]byte, len(privKeys))
+	for i, privKey := range privKeys {
+		sig, err := privKey.Sign(msg)
+		require.NoError(t, err)
+		sigs[i] = sig
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 12; i++ {
+		i := i % len(privKeys)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.True(t, cache.verify(SchemeBasic, privKeys[i].PubKey().Bytes(), msg, sigs[i]))
+		}()
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, cache.len(), 2)
+}
@@ -0,0 +1,92 @@
+package bls12381
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+// ErrNotEnoughSignatureShares is returned when fewer valid signature shares
+// than the threshold are available.
+var ErrNotEnoughSignatureShares = errors.New("not enough valid signature shares")
+
+// SignatureShare is the signature share of a quorum member, along with the
+// public key share it is checked against.
+type SignatureShare struct {
+	ProTxHash   crypto.ProTxHash
+	PubKeyShare crypto.PubKey
+	Signature   []byte
+}
+
+// RecoverThresholdSignatureFromVerifiedShares recovers the threshold signature
+// of msg from the signature shares of the quorum members. Unlike
+// RecoverThresholdSignatureFromShares, it verifies every share against the
+// public key share of its member first, and only interpolates valid shares.
+//
+// It returns the proTxHashes of the members that sent invalid shares, in the
+// order of shares, together with the signature. The recovery fails with
+// ErrNotEnoughSignatureShares if fewer than threshold shares are valid, and
+// with ErrDuplicateSignatureShare, before verifying anything, if two shares
+// carry the same proTxHash: the member cannot be blamed for a share that may
+// have been duplicated by whoever collected the shares.
+func RecoverThresholdSignatureFromVerifiedShares(
+	msg []byte,
+	threshold int,
+	shares []SignatureShare,
+) ([]byte, []crypto.ProTxHash, error) {
+	if threshold < 1 {
+		return nil, nil, fmt.Errorf("invalid threshold %d", threshold)
+	}
+	seen := make(map[string]struct{}, len(shares))
+	for _, share := range shares {
+		if _, ok := seen[string(share.ProTxHash)]; ok {
+			return nil, nil, fmt.Errorf("%X: %w", share.ProTxHash, ErrDuplicateSignatureShare)
+		}
+		seen[string(share.ProTxHash)] = struct{}{}
+	}
+	valid := make([]bool, len(shares))
+	batch := NewBatchVerifier()
+	batched := make([]int, 0, len(shares))
+	for i, share := range shares {
+		if crypto.ProTxHashValidate(share.ProTxHash) != nil ||
+			share.PubKeyShare == nil || batch.Add(share.PubKeyShare, msg, share.Signature) != nil {
+			continue
+		}
+		batched = append(batched, i)
+	}
+	if len(batched) > 0 {
+		_, results := batch.Verify()
+		for j, i := range batched {
+			valid[i] = results[j]
+		}
+	}
+
+	var (
+		invalid   []crypto.ProTxHash
+		sigShares [][]byte
+		blsIDs    []BLSID
+	)
+	for i, share := range shares {
+		if !valid[i] {
+			invalid = append(invalid, share.ProTxHash)
+			continue
+		}
+		if len(sigShares) < threshold {
+			blsID, err := BLSIDFromProTxHash(share.ProTxHash)
+			if err != nil {
+				return nil, invalid, err
+			}
+			sigShares = append(sigShares, share.Signature)
+			blsIDs = append(blsIDs, blsID)
+		}
+	}
+	if len(sigShares) < threshold {
+		return nil, invalid, fmt.Errorf("%w: %d of %d", ErrNotEnoughSignatureShares, len(sigShares), threshold)
+	}
+	sig, err := RecoverThresholdSignatureFromShares(sigShares, blsIDs)
+	if err != nil {
+		return nil, invalid, err
+	}
+	return sig, invalid, nil
+}
@@ -0,0 +1,78 @@
+package bls12381
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+func TestRecoverThresholdSignatureFromVerifiedShares(t *testing.T) {
+	const threshold, n = 4, 7
+	keys, err := GenerateQuorumKeyShares(threshold, crypto.RandProTxHashes(n))
+	require.NoError(t, err)
+	msg := crypto.CRandBytes(32)
+	signShares := func() []SignatureShare {
+		shares := make([]SignatureShare, n)
+		for i, share := range keys.Shares {
+			shares[i] = SignatureShare{
+				ProTxHash:   share.ProTxHash,
+				PubKeyShare: share.PubKey,
+				Signature:   mustSign(t, share.PrivKey, msg),
+			}
+		}
+		return shares
+	}
+	proTxHash := func(i int) crypto.ProTxHash { return keys.Shares[i].ProTxHash }
+
+	t.Run("all valid", func(t *testing.T) {
+		sig, invalid, err := RecoverThresholdSignatureFromVerifiedShares(msg, threshold, signShares())
+		require.NoError(t, err)
+		assert.Empty(t, invalid)
+		assert.True(t, keys.ThresholdPublicKey.VerifySignature(msg, sig))
+	})
+
+	t.Run("some invalid", func(t *testing.T) {
+		shares := signShares()
+		shares[0].Signature = mustSign(t, keys.Shares[0].PrivKey, []byte("other message"))
+		shares[3].Signature = shares[4].Signature
+		shares[5].PubKeyShare = keys.Shares[6].PubKey
+		sig, invalid, err := RecoverThresholdSignatureFromVerifiedShares(msg, threshold, shares)
+		require.NoError(t, err)
+		assert.Equal(t, []crypto.ProTxHash{proTxHash(0), proTxHash(3), proTxHash(5)}, invalid)
+		assert.True(t, keys.ThresholdPublicKey.VerifySignature(msg, sig))
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		shares := signShares()
+		shares[1].Signature = shares[1].Signature[1:]
+		shares[2].PubKeyShare = nil
+		sig, invalid, err := RecoverThresholdSignatureFromVerifiedShares(msg, threshold, shares)
+		require.NoError(t, err)
+		assert.Equal(t, []crypto.ProTxHash{proTxHash(1), proTxHash(2)}, invalid)
+		assert.True(t, keys.ThresholdPublicKey.VerifySignature(msg, sig))
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		shares := append(signShares(), SignatureShare{
+			ProTxHash:   proTxHash(6),
+			PubKeyShare: keys.Shares[6].PubKey,
+			Signature:   mustSign(t, keys.Shares[6].PrivKey, []byte("other message")),
+		})
+		_, invalid, err := RecoverThresholdSignatureFromVerifiedShares(msg, threshold, shares)
+		assert.ErrorIs(t, err, ErrDuplicateSignatureShare)
+		assert.Empty(t, invalid)
+	})
+
+	t.Run("not enough valid", func(t *testing.T) {
+		shares := signShares()
+		for i := 0; i < n-threshold+1; i++ {
+			shares[i].Signature = shares[n-1].Signature
+		}
+		_, invalid, err := RecoverThresholdSignatureFromVerifiedShares(msg, threshold, shares)
+		assert.ErrorIs(t, err, ErrNotEnoughSignatureShares)
+		assert.Equal(t, []crypto.ProTxHash{proTxHash(0), proTxHash(1), proTxHash(2), proTxHash(3)}, invalid)
+	})
+}
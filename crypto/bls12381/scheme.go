@@ -0,0 +1,276 @@
+package bls12381
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dashpay/tenderdash/crypto"
+	tmbytes "github.com/dashpay/tenderdash/libs/bytes"
+)
+
+// Scheme is one of the signature schemes of the IETF BLS signature draft.
+// Keys are shared between schemes, but a signature only verifies under the
+// scheme it was produced with. The schemes differ in how they defend against
+// rogue public key attacks, and thus in how signatures may be aggregated.
+type Scheme uint8
+
+const (
+	// SchemeBasic is the basic scheme used by PrivKey.Sign and
+	// PubKey.VerifySignature. Aggregated signatures must sign distinct messages.
+	SchemeBasic Scheme = iota
+	// SchemeAugmented prepends the signer's public key to every message, so
+	// signatures of the same message by different keys can be aggregated.
+	SchemeAugmented
+	// SchemePoP requires every public key to be registered together with a
+	// proof of possession of its private key, see PopProve. Signatures of the
+	// same message can then be checked at once with PopFastAggregateVerify.
+	SchemePoP
+)
+
+// Domain separation tags of the G2 ciphersuites of the schemes.
+const (
+	basicSchemeDST = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_"
+	augSchemeDST   = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_AUG_"
+	popSchemeDST   = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+	// popProofDST is used for proofs of possession only.
+	popProofDST = "BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+)
+
+var (
+	schemeNames = [...]string{
+		SchemeBasic:     "basic",
+		SchemeAugmented: "augmented",
+		SchemePoP:       "pop",
+	}
+	schemeDSTs = [...]string{
+		SchemeBasic:     basicSchemeDST,
+		SchemeAugmented: augSchemeDST,
+		SchemePoP:       popSchemeDST,
+	}
+
+	errUnknownScheme = errors.New("unknown bls signature scheme")
+)
+
+// ParseScheme returns the scheme with the given name, as returned by
+// Scheme.String.
+func ParseScheme(name string) (Scheme, error) {
+	for scheme, schemeName := range schemeNames {
+		if schemeName == name {
+			return Scheme(scheme), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", errUnknownScheme, name)
+}
+
+// String returns the name of the scheme.
+func (s Scheme) String() string {
+	if !s.valid() {
+		return fmt.Sprintf("Scheme(%d)", uint8(s))
+	}
+	return schemeNames[s]
+}
+
+// MarshalText implements encoding.TextMarshaler, so that the scheme is
+// recorded by name in JSON.
+func (s Scheme) MarshalText() ([]byte, error) {
+	if !s.valid() {
+		return nil, fmt.Errorf("%w: %d", errUnknownScheme, uint8(s))
+	}
+	return []byte(schemeNames[s]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Scheme) UnmarshalText(text []byte) error {
+	scheme, err := ParseScheme(string(text))
+	if err != nil {
+		return err
+	}
+	*s = scheme
+	return nil
+}
+
+// Sign produces a signature of msg under the scheme.
+func (s Scheme) Sign(privKey PrivKey, msg []byte) ([]byte, error) {
+	if !s.valid() {
+		return nil, errUnknownScheme
+	}
+	if len(privKey) != PrivateKeySize {
+		return nil, errInvalidPrivateKeySize(len(privKey))
+	}
+	return defaultBackend.sign(s, privKey, msg)
+}
+
+// Verify reports whether sig is a signature of msg by pubKey under the scheme.
+// The public key at infinity never verifies, as the signature at infinity
+// would verify any message against it.
+func (s Scheme) Verify(pubKey PubKey, msg []byte, sig []byte) bool {
+	if !s.valid() || len(sig) != SignatureSize || isInfinity(pubKey) {
+		return false
+	}
+	return preparedPubKeys.verify(s, pubKey, msg, sig)
+}
+
+// verifyOnce is Verify for one-off public keys, such as aggregates, which it
+// decodes without the shared cache.
+func (s Scheme) verifyOnce(pubKey PubKey, msg []byte, sig []byte) bool {
+	if !s.valid() || len(sig) != SignatureSize || isInfinity(pubKey) {
+		return false
+	}
+	return defaultBackend.verify(s, pubKey, msg, sig)
+}
+
+// AggregateVerify reports whether sig is an aggregate of signatures of msgs[i]
+// by pubKeys[i] under the scheme. The basic scheme requires messages to be
+// pairwise distinct and the verification fails otherwise.
+func (s Scheme) AggregateVerify(pubKeys []crypto.PubKey, msgs [][]byte, sig []byte) bool {
+	if !s.valid() || len(pubKeys) == 0 || len(pubKeys) != len(msgs) || len(sig) != SignatureSize {
+		return false
+	}
+	if s == SchemeBasic && !distinctMessages(msgs) {
+		return false
+	}
+	pks, err := pubKeysBytes(pubKeys)
+	if err != nil || checkNotInfinity(pks) != nil {
+		return false
+	}
+	return defaultBackend.aggregateVerify(s, pks, msgs, sig)
+}
+
+// distinctMessages reports whether msgs are pairwise distinct.
+func distinctMessages(msgs [][]byte) bool {
+	seen := make(map[string]struct{}, len(msgs))
+	for _, msg := range msgs {
+		if _, ok := seen[string(msg)]; ok {
+			return false
+		}
+		seen[string(msg)] = struct{}{}
+	}
+	return true
+}
+
+// valid reports whether s is one of the known schemes.
+func (s Scheme) valid() bool {
+	return int(s) < len(schemeNames)
+}
+
+// dst returns the domain separation tag the scheme hashes messages with.
+func (s Scheme) dst() string {
+	return schemeDSTs[s]
+}
+
+// augment returns the message that is actually hashed when pk signs msg.
+func (s Scheme) augment(pk, msg []byte) []byte {
+	if s != SchemeAugmented {
+		return msg
+	}
+	augmented := make([]byte, 0, len(pk)+len(msg))
+	return append(append(augmented, pk...), msg...)
+}
+
+// PopProve returns a proof of possession of privKey, to be published together
+// with its public key when registering it for the PoP scheme.
+func PopProve(privKey PrivKey) ([]byte, error) {
+	if len(privKey) != PrivateKeySize {
+		return nil, errInvalidPrivateKeySize(len(privKey))
+	}
+	return defaultBackend.popProve(privKey)
+}
+
+// PopVerify reports whether proof is a valid proof of possession of the private
+// key of pubKey. There is no proof for the public key at infinity.
+func PopVerify(pubKey PubKey, proof []byte) bool {
+	if len(proof) != SignatureSize || isInfinity(pubKey) {
+		return false
+	}
+	return defaultBackend.popVerify(pubKey, proof)
+}
+
+// PopFastAggregateVerify reports whether sig is an aggregate of PoP scheme
+// signatures of msg by every one of pubKeys.
+//
+// Unlike VerifyAggregateSameMessage, this is safe against rogue public keys as
+// long as the proof of possession of every public key was checked with
+// PopVerify beforehand.
+func PopFastAggregateVerify(pubKeys []crypto.PubKey, msg []byte, sig []byte) bool {
+	if len(pubKeys) == 0 {
+		return false
+	}
+	aggregate, err := AggregatePubKeys(pubKeys)
+	if err != nil {
+		return false
+	}
+	return SchemePoP.verifyOnce(aggregate, msg, sig)
+}
+
+// SchemeSignature is a signature together with the scheme it was produced with.
+type SchemeSignature struct {
+	Scheme    Scheme           `json:"scheme"`
+	Signature tmbytes.HexBytes `json:"signature"`
+}
+
+// SignWithScheme signs msg under the given scheme and records the scheme
+// alongside the signature.
+func SignWithScheme(scheme Scheme, privKey PrivKey, msg []byte) (SchemeSignature, error) {
+	sig, err := scheme.Sign(privKey, msg)
+	if err != nil {
+		return SchemeSignature{}, err
+	}
+	return SchemeSignature{Scheme: scheme, Signature: sig}, nil
+}
+
+// Verify reports whether the signature is a signature of msg by pubKey under
+// its recorded scheme.
+func (s SchemeSignature) Verify(pubKey PubKey, msg []byte) bool {
+	return s.Scheme.Verify(pubKey, msg, s.Signature)
+}
+
+// SchemePubKey is a public key together with the scheme its signatures are
+// produced with. The same key verifies under every scheme, so the record is
+// what stops a signature of another scheme from being accepted for the key.
+type SchemePubKey struct {
+	Scheme Scheme `json:"scheme"`
+	PubKey PubKey `json:"pub_key"`
+}
+
+// Verify reports whether sig is a signature of msg by the key, under the
+// scheme of the key. Signatures recorded with another scheme never verify.
+func (k SchemePubKey) Verify(msg []byte, sig SchemeSignature) bool {
+	return sig.Scheme == k.Scheme && k.Scheme.Verify(k.PubKey, msg, sig.Signature)
+}
+
+// PossessionProof is the record a validator publishes at onboarding to
+// register its public key for the PoP scheme.
+type PossessionProof struct {
+	PubKey PubKey           `json:"pub_key"`
+	Proof  tmbytes.HexBytes `json:"proof"`
+}
+
+// NewPossessionProof proves possession of privKey.
+func NewPossessionProof(privKey PrivKey) (PossessionProof, error) {
+	proof, err := PopProve(privKey)
+	if err != nil {
+		return PossessionProof{}, err
+	}
+	pubKey, err := defaultBackend.publicKey(privKey)
+	if err != nil {
+		return PossessionProof{}, err
+	}
+	return PossessionProof{PubKey: pubKey, Proof: proof}, nil
+}
+
+// Validate checks the public key and its proof of possession.
+func (p PossessionProof) Validate() error {
+	if err := p.PubKey.Validate(); err != nil {
+		return err
+	}
+	if !PopVerify(p.PubKey, p.Proof) {
+		return errors.New("invalid proof of possession")
+	}
+	return nil
+}
+
+// SchemePubKey returns the public key registered for the PoP scheme, once the
+// proof was checked with Validate.
+func (p PossessionProof) SchemePubKey() SchemePubKey {
+	return SchemePubKey{Scheme: SchemePoP, PubKey: p.PubKey}
+}
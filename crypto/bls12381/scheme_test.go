@@ -0,0 +1,234 @@
+// nolint:lll
+package bls12381
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+func TestSchemeVectors(t *testing.T) {
+	// vectors of the dashpay/bls-signatures schemes
+	sk1 := PrivKey(bytes.Repeat([]byte{1}, PrivateKeySize))
+	sk2 := make(PrivKey, PrivateKeySize)
+	for i := range sk2 {
+		sk2[i] = byte(i * 314159 % 256)
+	}
+	msg := []byte{3, 1, 4, 1, 5, 9}
+	testCases := []struct {
+		scheme  Scheme
+		refSig1 string
+		refSig2 string
+		refSigA string
+	}{
+		{
+			scheme:  SchemeBasic,
+			refSig1: "96ba34fac33c7f129d602a0bc8a3d43f9abc014eceaab7359146b4b150e57b808645738f35671e9e10e0d862a30cab70074eb5831d13e6a5b162d01eebe687d0164adbd0a864370a7c222a2768d7704da254f1bf1823665bc2361f9dd8c00e99",
+			refSig2: "a402790932130f766af11ba716536683d8c4cfa51947e4f9081fedd692d6dc0cac5b904bee5ea6e25569e36d7be4ca59069a96e34b7f700758b716f9494aaa59a96e74d14a3b552a9a6bc129e717195b9d6006fd6d5cef4768c022e0f7316abf",
+			refSigA: "987cfd3bcd62280287027483f29c55245ed831f51dd6bd999a6ff1a1f1f1f0b647778b0167359c71505558a76e158e66181ee5125905a642246b01e7fa5ee53d68a4fe9bfb29a8e26601f0b9ad577ddd18876a73317c216ea61f430414ec51c5",
+		},
+		{
+			scheme:  SchemeAugmented,
+			refSig1: "8180f02ccb72e922b152fcedbe0e1d195210354f70703658e8e08cbebf11d4970eab6ac3ccf715f3fb876df9a9797abd0c1af61aaeadc92c2cfe5c0a56c146cc8c3f7151a073cf5f16df38246724c4aed73ff30ef5daa6aacaed1a26ecaa336b",
+			refSig2: "99111eeafb412da61e4c37d3e806c6fd6ac9f3870e54da9222ba4e494822c5b7656731fa7a645934d04b559e9261b86201bbee57055250a459a2da10e51f9c1a6941297ffc5d970a557236d0bdeb7cf8ff18800b08633871a0f0a7ea42f47480",
+			refSigA: "8c5d03f9dae77e19a5945a06a214836edb8e03b851525d84b9de6440e68fc0ca7303eeed390d863c9b55a8cf6d59140a01b58847881eb5af67734d44b2555646c6616c39ab88d253299acc1eb1b19ddb9bfcbe76e28addf671d116c052bb1847",
+		},
+		{
+			scheme:  SchemePoP,
+			refSig1: "9550fb4e7f7e8cc4a90be8560ab5a798b0b23000b6a54a2117520210f986f3f281b376f259c0b78062d1eb3192b3d9bb049f59ecc1b03a7049eb665e0df36494ae4cb5f1136ccaeefc9958cb30c3333d3d43f07148c386299a7b1bfc0dc5cf7c",
+			refSig2: "a69036bc11ae5efcbf6180afe39addde7e27731ec40257bfdc3c37f17b8df68306a34ebd10e9e32a35253750df5c87c2142f8207e8d5654712b4e554f585fb6846ff3804e429a9f8a1b4c56b75d0869ed67580d789870babe2c7c8a9d51e7b2a",
+			refSigA: "a4ea742bcdc1553e9ca4e560be7e5e6c6efa6a64dddf9ca3bb2854233d85a6aac1b76ec7d103db4e33148b82af9923db05934a6ece9a7101cd8a9d47ce27978056b0f5900021818c45698afdd6cf8a6b6f7fee1f0b43716f55e413d4b87a6039",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.scheme.String(), func(t *testing.T) {
+			sig1, err := tc.scheme.Sign(sk1, msg)
+			require.NoError(t, err)
+			sig2, err := tc.scheme.Sign(sk2, msg)
+			require.NoError(t, err)
+			sigA, err := AggregateSignatures([][]byte{sig1, sig2})
+			require.NoError(t, err)
+			assert.Equal(t, tc.refSig1, hex.EncodeToString(sig1))
+			assert.Equal(t, tc.refSig2, hex.EncodeToString(sig2))
+			assert.Equal(t, tc.refSigA, hex.EncodeToString(sigA))
+
+			pk1, pk2 := sk1.PubKey().(PubKey), sk2.PubKey().(PubKey)
+			assert.True(t, tc.scheme.Verify(pk1, msg, sig1))
+			assert.True(t, tc.scheme.Verify(pk2, msg, sig2))
+			for _, other := range []Scheme{SchemeBasic, SchemeAugmented, SchemePoP} {
+				if other != tc.scheme {
+					assert.False(t, other.Verify(pk1, msg, sig1), "verified under %s", other)
+				}
+			}
+			// the basic scheme rejects aggregates of one message
+			assert.Equal(t, tc.scheme != SchemeBasic, tc.scheme.AggregateVerify([]crypto.PubKey{pk1, pk2}, [][]byte{msg, msg}, sigA))
+		})
+	}
+}
+
+func TestAugmentedSchemeAggregateVector(t *testing.T) {
+	msg1 := []byte{1, 2, 3, 40}
+	msg2 := []byte{5, 6, 70, 201}
+	msg3 := []byte{9, 10, 11, 12, 13}
+	msg4 := []byte{15, 63, 244, 92, 0, 1}
+	sk1 := genPrivKey(bytes.NewReader(bytes.Repeat([]byte{2}, SeedSize)))
+	sk2 := genPrivKey(bytes.NewReader(bytes.Repeat([]byte{3}, SeedSize)))
+	pk1, pk2 := sk1.PubKey(), sk2.PubKey()
+
+	signers := []PrivKey{sk1, sk2, sk2, sk1, sk1, sk1}
+	pubKeys := []crypto.PubKey{pk1, pk2, pk2, pk1, pk1, pk1}
+	msgs := [][]byte{msg1, msg2, msg1, msg3, msg1, msg4}
+	sigs := make([][]byte, len(msgs))
+	for i, msg := range msgs {
+		sig, err := SchemeAugmented.Sign(signers[i], msg)
+		require.NoError(t, err)
+		sigs[i] = sig
+	}
+	aggSig, err := AggregateSignatures(sigs)
+	require.NoError(t, err)
+	assert.Equal(t, "a1d5360dcb418d33b29b90b912b4accde535cf0e52caf467a005dc632d9f7af44b6c4e9acd46eac218b28cdb07a3e3bc087df1cd1e3213aa4e11322a3ff3847bbba0b2fd19ddc25ca964871997b9bceeab37a4c2565876da19382ea32a962200", hex.EncodeToString(aggSig))
+	assert.True(t, SchemeAugmented.AggregateVerify(pubKeys, msgs, aggSig))
+	assert.False(t, SchemeAugmented.AggregateVerify(pubKeys[1:], msgs[1:], aggSig))
+}
+
+func TestPopProveVector(t *testing.T) {
+	sk := genPrivKey(bytes.NewReader(bytes.Repeat([]byte{4}, SeedSize)))
+	proof, err := PopProve(sk)
+	require.NoError(t, err)
+	assert.Equal(t, "84f709159435f0dc73b3e8bf6c78d85282d19231555a8ee3b6e2573aaf66872d9203fefa1ef700e34e7c3f3fb28210100558c6871c53f1ef6055b9f06b0d1abe22ad584ad3b957f3018a8f58227c6c716b1e15791459850f2289168fa0cf9115", hex.EncodeToString(proof))
+	assert.True(t, PopVerify(sk.PubKey().(PubKey), proof))
+	assert.False(t, PopVerify(GenPrivKey().PubKey().(PubKey), proof))
+	assert.False(t, PopVerify(sk.PubKey().(PubKey), proof[1:]))
+}
+
+func TestSchemeInfinityPubKey(t *testing.T) {
+	infinitySig := append([]byte{0xc0}, make([]byte, SignatureSize-1)...)
+	assert.False(t, PopVerify(infinityPubKey(), infinitySig))
+
+	privKey := GenPrivKey()
+	msgs := [][]byte{[]byte("signed"), []byte("not signed")}
+	for _, scheme := range []Scheme{SchemeBasic, SchemeAugmented, SchemePoP} {
+		sig, err := scheme.Sign(privKey, msgs[0])
+		require.NoError(t, err)
+		pubKeys := []crypto.PubKey{privKey.PubKey(), infinityPubKey()}
+		assert.False(t, scheme.AggregateVerify(pubKeys, msgs, sig), scheme)
+	}
+}
+
+func TestPopFastAggregateVerify(t *testing.T) {
+	msg := []byte("block hash")
+	privKeys := []PrivKey{GenPrivKey(), GenPrivKey(), GenPrivKey()}
+	pubKeys := make([]crypto.PubKey, len(privKeys))
+	sigs := make([][]byte, len(privKeys))
+	for i, privKey := range privKeys {
+		proof, err := NewPossessionProof(privKey)
+		require.NoError(t, err)
+		require.NoError(t, proof.Validate())
+		pubKeys[i] = proof.PubKey
+		sig, err := SchemePoP.Sign(privKey, msg)
+		require.NoError(t, err)
+		sigs[i] = sig
+	}
+	aggSig, err := AggregateSignatures(sigs)
+	require.NoError(t, err)
+	assert.True(t, PopFastAggregateVerify(pubKeys, msg, aggSig))
+	assert.False(t, PopFastAggregateVerify(pubKeys[1:], msg, aggSig))
+	assert.False(t, PopFastAggregateVerify(pubKeys, []byte("other"), aggSig))
+	assert.False(t, PopFastAggregateVerify(nil, msg, aggSig))
+
+	// basic scheme signatures do not verify under the PoP scheme
+	basicSig, err := AggregateSignatures([][]byte{mustSign(t, privKeys[0], msg), mustSign(t, privKeys[1], msg), mustSign(t, privKeys[2], msg)})
+	require.NoError(t, err)
+	assert.False(t, PopFastAggregateVerify(pubKeys, msg, basicSig))
+}
+
+func TestPopRogueKey(t *testing.T) {
+	msg := []byte("transfer everything")
+	victim := GenPrivKey().PubKey().(PubKey)
+
+	// the attacker publishes rogue = x*g1 - victim, for which it does not know
+	// the private key, and alone forges an aggregate of both "signers"
+	x := GenPrivKey()
+	minusOne := scalarToBytes(new(big.Int).Sub(curveOrder, big.NewInt(1)))
+	negVictim, err := defaultBackend.mulPubKey(victim, minusOne)
+	require.NoError(t, err)
+	rogue, err := AggregatePubKeys([]crypto.PubKey{x.PubKey(), PubKey(negVictim)})
+	require.NoError(t, err)
+	forged, err := SchemePoP.Sign(x, msg)
+	require.NoError(t, err)
+	assert.True(t, PopFastAggregateVerify([]crypto.PubKey{victim, rogue}, msg, forged))
+
+	// which is why keys must be registered with a proof of possession
+	proof, err := PopProve(x)
+	require.NoError(t, err)
+	assert.Error(t, PossessionProof{PubKey: rogue, Proof: proof}.Validate())
+}
+
+func TestSchemeJSON(t *testing.T) {
+	privKey := GenPrivKey()
+	msg := []byte("message")
+	for _, scheme := range []Scheme{SchemeBasic, SchemeAugmented, SchemePoP} {
+		sig, err := SignWithScheme(scheme, privKey, msg)
+		require.NoError(t, err)
+		data, err := json.Marshal(sig)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"scheme":"`+scheme.String()+`"`)
+
+		var decoded SchemeSignature
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, sig, decoded)
+		assert.True(t, decoded.Verify(privKey.PubKey().(PubKey), msg))
+	}
+
+	var sig SchemeSignature
+	assert.Error(t, json.Unmarshal([]byte(`{"scheme":"unknown"}`), &sig))
+	var pubKey SchemePubKey
+	assert.Error(t, json.Unmarshal([]byte(`{"scheme":"unknown"}`), &pubKey))
+	_, err := json.Marshal(SchemeSignature{Scheme: 7})
+	assert.Error(t, err)
+	_, err = Scheme(7).Sign(privKey, msg)
+	assert.Error(t, err)
+	assert.False(t, Scheme(7).Verify(privKey.PubKey().(PubKey), msg, make([]byte, SignatureSize)))
+
+	proof, err := NewPossessionProof(privKey)
+	require.NoError(t, err)
+	data, err := json.Marshal(proof)
+	require.NoError(t, err)
+	var decoded PossessionProof
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.NoError(t, decoded.Validate())
+}
+
+func TestSchemePubKey(t *testing.T) {
+	privKey := GenPrivKey()
+	msg := []byte("message")
+	proof, err := NewPossessionProof(privKey)
+	require.NoError(t, err)
+	require.NoError(t, proof.Validate())
+	pubKey := proof.SchemePubKey()
+	assert.Equal(t, SchemePoP, pubKey.Scheme)
+
+	data, err := json.Marshal(pubKey)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"scheme":"pop"`)
+	var decoded SchemePubKey
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, pubKey, decoded)
+
+	for _, scheme := range []Scheme{SchemeBasic, SchemeAugmented, SchemePoP} {
+		sig, err := SignWithScheme(scheme, privKey, msg)
+		require.NoError(t, err)
+		assert.Equal(t, scheme == SchemePoP, decoded.Verify(msg, sig), scheme)
+		// the same signature bytes recorded under the key's scheme
+		relabeled := SchemeSignature{Scheme: SchemePoP, Signature: sig.Signature}
+		assert.Equal(t, scheme == SchemePoP, decoded.Verify(msg, relabeled), scheme)
+	}
+}
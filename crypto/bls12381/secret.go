@@ -0,0 +1,135 @@
+package bls12381
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrSecretKeyDestroyed is returned when a secret key is used after Destroy.
+var ErrSecretKeyDestroyed = errors.New("secret key has been destroyed")
+
+// SecretKey holds a private key for its whole lifetime, unlike PrivKey, which
+// is a plain byte slice that is copied freely.
+//
+// The key is kept in a single buffer, outside of the Go heap where the
+// platform allows it, which Destroy wipes. It is decoded by the backend once
+// and signing does not copy it into long-lived buffers. A SecretKey is safe for
+// concurrent use.
+//
+// With the pure Go backend, the buffer is the only copy of the key. The cgo
+// backend also holds a native copy in the dashpay/bls-signatures bindings.
+// The bindings do not let it be freed explicitly: it is freed and wiped only
+// when the garbage collector finalizes it, some time after Destroy.
+type SecretKey struct {
+	mtx sync.RWMutex
+	// buf holds the private key, nil once the key is destroyed
+	buf      []byte
+	locked   bool
+	prepared preparedSecretKey
+	pubKey   PubKey
+}
+
+// NewSecretKey copies privKey into a new secret key. The caller should wipe
+// privKey once it is no longer needed, see PrivKey.Wipe.
+func NewSecretKey(privKey PrivKey) (*SecretKey, error) {
+	if err := checkPrivKey(privKey); err != nil {
+		return nil, err
+	}
+	buf, err := allocSecret(PrivateKeySize)
+	if err != nil {
+		return nil, err
+	}
+	copy(buf, privKey)
+	k := &SecretKey{buf: buf}
+	if k.prepared, err = defaultBackend.prepareSecretKey(buf); err != nil {
+		_ = freeSecret(buf)
+		return nil, err
+	}
+	pk, err := k.prepared.publicKey()
+	if err != nil {
+		k.prepared.destroy()
+		_ = freeSecret(buf)
+		return nil, err
+	}
+	k.pubKey = pk
+	return k, nil
+}
+
+// PubKey returns the public key of the secret key. It remains available after
+// the key is destroyed.
+func (k *SecretKey) PubKey() PubKey {
+	return k.pubKey
+}
+
+// Sign is PrivKey.Sign.
+func (k *SecretKey) Sign(msg []byte) ([]byte, error) {
+	return k.SignScheme(SchemeBasic, msg)
+}
+
+// SignDigest is PrivKey.SignDigest.
+func (k *SecretKey) SignDigest(msg []byte) ([]byte, error) {
+	return k.Sign(msg)
+}
+
+// SignScheme produces a signature of msg under the given scheme, see
+// Scheme.Sign.
+func (k *SecretKey) SignScheme(scheme Scheme, msg []byte) ([]byte, error) {
+	if !scheme.valid() {
+		return nil, errUnknownScheme
+	}
+	k.mtx.RLock()
+	defer k.mtx.RUnlock()
+	if k.buf == nil {
+		return nil, ErrSecretKeyDestroyed
+	}
+	return k.prepared.sign(scheme, msg)
+}
+
+// LockMemory prevents the memory holding the key from being swapped to disk.
+// It is only supported on Linux, and may fail if the process exceeds its limit
+// of locked memory.
+func (k *SecretKey) LockMemory() error {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+	if k.buf == nil {
+		return ErrSecretKeyDestroyed
+	}
+	if k.locked {
+		return nil
+	}
+	if err := lockSecret(k.buf); err != nil {
+		return err
+	}
+	k.locked = true
+	return nil
+}
+
+// Destroy wipes the buffer of the key, releases its memory and drops the
+// reference to the native copy of the cgo backend, which is wiped once it is
+// garbage collected. The key can no longer sign afterwards. Destroying a key
+// twice is a no-op.
+func (k *SecretKey) Destroy() error {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+	if k.buf == nil {
+		return nil
+	}
+	k.prepared.destroy()
+	err := freeSecret(k.buf)
+	k.buf, k.prepared, k.locked = nil, nil, false
+	return err
+}
+
+// Destroyed reports whether Destroy was called.
+func (k *SecretKey) Destroyed() bool {
+	k.mtx.RLock()
+	defer k.mtx.RUnlock()
+	return k.buf == nil
+}
+
+// wipeBytes overwrites b with zeros.
+func wipeBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,23 @@
+//go:build cgo && !purego
+
+package bls12381
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretKeyDestroyDropsNativeKey(t *testing.T) {
+	key, err := NewSecretKey(GenPrivKey())
+	require.NoError(t, err)
+	prepared, ok := key.prepared.(*cgoPreparedSecretKey)
+	require.True(t, ok)
+	require.NotNil(t, prepared.key)
+
+	// the native copy is wiped by its finalizer, Destroy only makes it
+	// unreachable
+	require.NoError(t, key.Destroy())
+	assert.Nil(t, prepared.key)
+}
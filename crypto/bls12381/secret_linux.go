@@ -0,0 +1,30 @@
+//go:build linux
+
+package bls12381
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// allocSecret allocates size bytes for secret material in a private anonymous
+// mapping, outside of the Go heap and excluded from core dumps.
+func allocSecret(size int) ([]byte, error) {
+	buf, err := unix.Mmap(-1, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_PRIVATE|unix.MAP_ANONYMOUS)
+	if err != nil {
+		return nil, err
+	}
+	// best effort, as older kernels do not support it
+	_ = unix.Madvise(buf, unix.MADV_DONTDUMP)
+	return buf, nil
+}
+
+// lockSecret locks the pages of buf in memory.
+func lockSecret(buf []byte) error {
+	return unix.Mlock(buf)
+}
+
+// freeSecret wipes and unmaps buf, which also unlocks it.
+func freeSecret(buf []byte) error {
+	wipeBytes(buf)
+	return unix.Munmap(buf)
+}
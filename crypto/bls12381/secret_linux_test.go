@@ -0,0 +1,46 @@
+//go:build linux
+
+package bls12381
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestSecretKeyLockMemory(t *testing.T) {
+	key, err := NewSecretKey(GenPrivKey())
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, key.Destroy()) })
+
+	before := lockedMemory(t)
+	err = key.LockMemory()
+	if errors.Is(err, unix.EPERM) || errors.Is(err, unix.ENOMEM) {
+		t.Skipf("cannot lock memory: %v", err)
+	}
+	require.NoError(t, err)
+	require.NoError(t, key.LockMemory(), "locking twice")
+	assert.NotEqual(t, before, lockedMemory(t))
+
+	require.NoError(t, key.Destroy())
+	assert.Equal(t, before, lockedMemory(t))
+}
+
+// lockedMemory returns the VmLck line of the status of the process.
+func lockedMemory(t *testing.T) string {
+	t.Helper()
+	status, err := os.ReadFile("/proc/self/status")
+	require.NoError(t, err)
+	for _, line := range strings.Split(string(status), "\n") {
+		if strings.HasPrefix(line, "VmLck:") {
+			return line
+		}
+	}
+	t.Skip("locked memory is not reported")
+	return ""
+}
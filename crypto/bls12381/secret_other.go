@@ -0,0 +1,26 @@
+//go:build !linux
+
+package bls12381
+
+import (
+	"errors"
+)
+
+var errLockMemoryUnsupported = errors.New("locking memory is only supported on linux")
+
+// allocSecret allocates size bytes for secret material. The Go heap does not
+// move objects, so the bytes stay where they are until they are wiped.
+func allocSecret(size int) ([]byte, error) {
+	return make([]byte, size), nil
+}
+
+// lockSecret is not supported on this platform.
+func lockSecret([]byte) error {
+	return errLockMemoryUnsupported
+}
+
+// freeSecret wipes buf.
+func freeSecret(buf []byte) error {
+	wipeBytes(buf)
+	return nil
+}
@@ -0,0 +1,139 @@
+package bls12381
+
+import (
+	"bytes"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+func TestSecretKeySign(t *testing.T) {
+	privKey := GenPrivKey()
+	key, err := NewSecretKey(privKey)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, key.Destroy()) })
+	assert.Equal(t, privKey.PubKey(), key.PubKey())
+
+	// the secret key holds its own copy of the private key
+	origKey := append(PrivKey(nil), privKey...)
+	privKey.Wipe()
+	assert.Equal(t, make([]byte, PrivateKeySize), []byte(privKey))
+
+	msg := crypto.CRandBytes(32)
+	for _, scheme := range []Scheme{SchemeBasic, SchemeAugmented, SchemePoP} {
+		want, err := scheme.Sign(origKey, msg)
+		require.NoError(t, err)
+		got, err := key.SignScheme(scheme, msg)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, scheme)
+	}
+	sig, err := key.Sign(msg)
+	require.NoError(t, err)
+	assert.True(t, key.PubKey().VerifySignature(msg, sig))
+	_, err = key.SignScheme(Scheme(42), msg)
+	assert.Error(t, err)
+
+	_, err = NewSecretKey(make([]byte, PrivateKeySize))
+	assert.ErrorIs(t, err, ErrPrivKeyInvalid)
+	_, err = NewSecretKey(origKey[1:])
+	assert.ErrorIs(t, err, ErrPrivKeyInvalidSize)
+}
+
+func TestSecretKeyDestroy(t *testing.T) {
+	privKey := GenPrivKey()
+	key, err := NewSecretKey(privKey)
+	require.NoError(t, err)
+	prepared := key.prepared
+
+	require.NoError(t, key.Destroy())
+	assert.True(t, key.Destroyed())
+	assert.Nil(t, key.buf)
+	assert.Nil(t, key.prepared)
+	if prepared, ok := prepared.(*goPreparedSecretKey); ok {
+		assert.Nil(t, prepared.sk)
+	}
+
+	_, err = key.Sign([]byte("msg"))
+	assert.ErrorIs(t, err, ErrSecretKeyDestroyed)
+	assert.ErrorIs(t, key.LockMemory(), ErrSecretKeyDestroyed)
+	assert.NoError(t, key.Destroy())
+	assert.Equal(t, privKey.PubKey(), key.PubKey())
+}
+
+func TestSecretKeyConcurrentDestroy(t *testing.T) {
+	key, err := NewSecretKey(GenPrivKey())
+	require.NoError(t, err)
+	msg := crypto.CRandBytes(32)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sig, err := key.Sign(msg)
+			if err != nil {
+				assert.ErrorIs(t, err, ErrSecretKeyDestroyed)
+				return
+			}
+			assert.True(t, key.PubKey().VerifySignature(msg, sig))
+		}()
+	}
+	require.NoError(t, key.Destroy())
+	wg.Wait()
+}
+
+// The tests below audit that signing does not leave copies of the private key
+// behind in buffers that outlive the operation.
+
+func TestSecretKeyDoesNotCopyKey(t *testing.T) {
+	key, err := NewSecretKey(GenPrivKey())
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, key.Destroy()) })
+	prepared, ok := key.prepared.(*goPreparedSecretKey)
+	if !ok {
+		t.Skip("the backend holds the key in native memory")
+	}
+	// the pure Go backend decodes the key from the buffer of the secret key
+	// for every operation instead of keeping a copy of its own
+	assert.Same(t, &key.buf[0], &prepared.sk[0])
+}
+
+func TestWithScalarWipesScalar(t *testing.T) {
+	privKey := GenPrivKey()
+	origKey := append([]byte(nil), privKey...)
+	var (
+		scalar *big.Int
+		words  []big.Word
+	)
+	_, err := withScalar(privKey, func(s *big.Int) ([]byte, error) {
+		scalar, words = s, s.Bits()
+		assert.Equal(t, new(big.Int).SetBytes(origKey), s)
+		return goBackend{}.sign(SchemeBasic, privKey, []byte("msg"))
+	})
+	require.NoError(t, err)
+	assert.Zero(t, scalar.Sign())
+	for _, word := range words {
+		assert.Zero(t, word)
+	}
+	assert.Equal(t, origKey, []byte(privKey), "the private key is left intact")
+}
+
+func TestScalarFromBytesReducesInPlace(t *testing.T) {
+	for _, sk := range [][]byte{
+		bytes.Repeat([]byte{0xff}, PrivateKeySize),
+		scalarToBytes(curveOrder),
+		scalarToBytes(new(big.Int).Sub(curveOrder, big.NewInt(1))),
+		crypto.CRandBytes(PrivateKeySize),
+	} {
+		want := new(big.Int).SetBytes(sk)
+		want.Mod(want, curveOrder)
+		got, err := scalarFromBytes(sk)
+		require.NoError(t, err)
+		assert.Equal(t, 0, want.Cmp(got), "%X", sk)
+	}
+}
@@ -0,0 +1,269 @@
+package bls12381
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+var (
+	// ErrSigningSessionClosed is returned for shares added to a session that
+	// recovered its signature or was cancelled.
+	ErrSigningSessionClosed = errors.New("signing session is closed")
+	// ErrDuplicateSignatureShare is returned for shares of members whose share
+	// the session already has.
+	ErrDuplicateSignatureShare = errors.New("duplicate signature share")
+	// ErrInvalidSignatureShare is returned for shares that do not verify
+	// against the public key share of their member.
+	ErrInvalidSignatureShare = errors.New("invalid signature share")
+)
+
+// SigningMember is a quorum member whose signature shares a session accepts.
+type SigningMember struct {
+	ProTxHash   crypto.ProTxHash
+	PubKeyShare crypto.PubKey
+}
+
+// SigningSessionParams describe the threshold signature a session recovers.
+type SigningSessionParams struct {
+	// QuorumHash and RequestID identify the session.
+	QuorumHash crypto.QuorumHash
+	RequestID  []byte
+	// Msg is the digest the members sign, see PubKey.VerifySignatureDigest.
+	Msg []byte
+	// Threshold is the number of shares the signature is recovered from.
+	Threshold int
+	// Members are the members of the quorum.
+	Members []SigningMember
+	// ThresholdPublicKey, if set, is checked against the recovered signature.
+	ThresholdPublicKey crypto.PubKey
+	// OnRecovered, if set, is called once with the recovered signature, from
+	// the goroutine that added the last share.
+	OnRecovered func(sig []byte)
+}
+
+// SigningSession collects the signature shares of the members of a quorum for
+// one request and recovers the threshold signature once it has enough of them.
+// It is safe for concurrent use, so shares can be added as they arrive from
+// any number of goroutines.
+//
+// Shares of unknown members, repeated shares and shares that do not verify
+// against the public key share of their member are dropped. The first
+// threshold valid shares are recovered, and later shares are rejected with
+// ErrSigningSessionClosed.
+type SigningSession struct {
+	quorumHash         crypto.QuorumHash
+	requestID          []byte
+	msg                []byte
+	threshold          int
+	members            map[string]signingMember
+	thresholdPublicKey crypto.PubKey
+	onRecovered        func(sig []byte)
+	stop               func() bool
+
+	mtx sync.Mutex
+	// received holds the members whose share is verified or being verified
+	received  map[string]struct{}
+	sigShares [][]byte
+	blsIDs    []BLSID
+	done      chan struct{}
+	sig       []byte
+	err       error
+}
+
+// signingMember is a member of a signing session, ready to verify its shares.
+type signingMember struct {
+	pubKeyShare *PreparedPubKey
+	blsID       BLSID
+}
+
+// NewSigningSession starts a signing session, which is cancelled with the
+// cause of ctx when ctx is done before the signature is recovered.
+func NewSigningSession(ctx context.Context, params SigningSessionParams) (*SigningSession, error) {
+	if len(params.QuorumHash) != crypto.QuorumHashSize {
+		return nil, fmt.Errorf("quorum hash has wrong size %d, expected %d", len(params.QuorumHash), crypto.QuorumHashSize)
+	}
+	if len(params.RequestID) == 0 {
+		return nil, errors.New("empty request id")
+	}
+	if params.Threshold < 1 || params.Threshold > len(params.Members) {
+		return nil, fmt.Errorf("threshold %d is out of range [1, %d]", params.Threshold, len(params.Members))
+	}
+	members := make(map[string]signingMember, len(params.Members))
+	for _, member := range params.Members {
+		blsID, err := BLSIDFromProTxHash(member.ProTxHash)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := members[string(member.ProTxHash)]; ok {
+			return nil, fmt.Errorf("duplicate member %X", member.ProTxHash)
+		}
+		pubKey, ok := member.PubKeyShare.(PubKey)
+		if !ok {
+			return nil, fmt.Errorf("public key share of %X is not BLS12-381 but %T", member.ProTxHash, member.PubKeyShare)
+		}
+		prepared, err := pubKey.Prepare()
+		if err != nil {
+			return nil, fmt.Errorf("public key share of %X: %w", member.ProTxHash, err)
+		}
+		members[string(member.ProTxHash)] = signingMember{pubKeyShare: prepared, blsID: blsID}
+	}
+
+	s := &SigningSession{
+		quorumHash:         params.QuorumHash.Copy(),
+		requestID:          append([]byte(nil), params.RequestID...),
+		msg:                append([]byte(nil), params.Msg...),
+		threshold:          params.Threshold,
+		members:            members,
+		thresholdPublicKey: params.ThresholdPublicKey,
+		onRecovered:        params.OnRecovered,
+		received:           make(map[string]struct{}, params.Threshold),
+		done:               make(chan struct{}),
+	}
+	s.stop = context.AfterFunc(ctx, func() {
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+		s.close(nil, context.Cause(ctx))
+	})
+	return s, nil
+}
+
+// QuorumHash returns the hash of the quorum that signs.
+func (s *SigningSession) QuorumHash() crypto.QuorumHash {
+	return s.quorumHash
+}
+
+// RequestID returns the id of the signing request.
+func (s *SigningSession) RequestID() []byte {
+	return s.requestID
+}
+
+// AddShare verifies the signature share of the member with the given
+// proTxHash and keeps it if it is valid. It recovers the threshold signature
+// once threshold shares are kept, and returns the error of the recovery then.
+func (s *SigningSession) AddShare(proTxHash crypto.ProTxHash, sigShare []byte) error {
+	member, ok := s.members[string(proTxHash)]
+	if !ok {
+		return fmt.Errorf("%X: %w", proTxHash, errUnknownMember)
+	}
+	if err := s.reserve(proTxHash); err != nil {
+		return err
+	}
+
+	// verify outside of the lock, so that shares are verified in parallel
+	valid := member.pubKeyShare.VerifySignatureDigest(s.msg, sigShare)
+
+	s.mtx.Lock()
+	if !valid {
+		delete(s.received, string(proTxHash))
+		s.mtx.Unlock()
+		return fmt.Errorf("%X: %w", proTxHash, ErrInvalidSignatureShare)
+	}
+	if s.closed() {
+		s.mtx.Unlock()
+		return ErrSigningSessionClosed
+	}
+	s.sigShares = append(s.sigShares, append([]byte(nil), sigShare...))
+	s.blsIDs = append(s.blsIDs, member.blsID)
+	if len(s.sigShares) < s.threshold {
+		s.mtx.Unlock()
+		return nil
+	}
+	sig, err := s.recoverSignature()
+	s.close(sig, err)
+	s.mtx.Unlock()
+
+	s.stop()
+	if err == nil && s.onRecovered != nil {
+		s.onRecovered(sig)
+	}
+	return err
+}
+
+// Done returns a channel that is closed when the session recovered the
+// signature, failed to, or was cancelled.
+func (s *SigningSession) Done() <-chan struct{} {
+	return s.done
+}
+
+// Result returns the recovered signature, or why the session failed or was
+// cancelled. It returns ErrNotEnoughSignatureShares while the session runs.
+func (s *SigningSession) Result() ([]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if !s.closed() {
+		return nil, fmt.Errorf("%w: %d of %d", ErrNotEnoughSignatureShares, len(s.sigShares), s.threshold)
+	}
+	return s.sig, s.err
+}
+
+// Wait waits for the session to end and returns its result, or returns the
+// error of ctx if ctx is done first. The session keeps running then.
+func (s *SigningSession) Wait(ctx context.Context) ([]byte, error) {
+	select {
+	case <-s.done:
+		return s.Result()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Cancel ends the session without a signature. Cancelling a session that has
+// ended does nothing.
+func (s *SigningSession) Cancel() {
+	s.mtx.Lock()
+	s.close(nil, context.Canceled)
+	s.mtx.Unlock()
+	s.stop()
+}
+
+// reserve marks the share of the member as received, so that concurrent
+// copies of it are dropped while it is verified.
+func (s *SigningSession) reserve(proTxHash crypto.ProTxHash) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.closed() {
+		return ErrSigningSessionClosed
+	}
+	if _, ok := s.received[string(proTxHash)]; ok {
+		return fmt.Errorf("%X: %w", proTxHash, ErrDuplicateSignatureShare)
+	}
+	s.received[string(proTxHash)] = struct{}{}
+	return nil
+}
+
+// recoverSignature recovers the threshold signature from the kept shares. The
+// caller holds the lock.
+func (s *SigningSession) recoverSignature() ([]byte, error) {
+	sig, err := RecoverThresholdSignatureFromShares(s.sigShares, s.blsIDs)
+	if err != nil {
+		return nil, err
+	}
+	if s.thresholdPublicKey != nil && !s.thresholdPublicKey.VerifySignatureDigest(s.msg, sig) {
+		return nil, errors.New("recovered signature does not verify against the threshold public key")
+	}
+	return sig, nil
+}
+
+// close ends the session with the given result unless it has ended already.
+// The caller holds the lock.
+func (s *SigningSession) close(sig []byte, err error) {
+	if s.closed() {
+		return
+	}
+	s.sig, s.err = sig, err
+	close(s.done)
+}
+
+// closed reports whether the session has ended. The caller holds the lock.
+func (s *SigningSession) closed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
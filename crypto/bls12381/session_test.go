@@ -0,0 +1,210 @@
+package bls12381
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+)
+
+// newTestSigningSession deals a quorum of n members and starts a session for a
+// random message.
+func newTestSigningSession(
+	ctx context.Context,
+	t *testing.T,
+	n, threshold int,
+	onRecovered func([]byte),
+) (*SigningSession, *QuorumKeyShares, []byte) {
+	t.Helper()
+	keys, err := GenerateQuorumKeyShares(threshold, crypto.RandProTxHashes(n))
+	require.NoError(t, err)
+	members := make([]SigningMember, n)
+	for i, share := range keys.Shares {
+		members[i] = SigningMember{ProTxHash: share.ProTxHash, PubKeyShare: share.PubKey}
+	}
+	msg := crypto.CRandBytes(32)
+	session, err := NewSigningSession(ctx, SigningSessionParams{
+		QuorumHash:         crypto.RandQuorumHash(),
+		RequestID:          crypto.CRandBytes(32),
+		Msg:                msg,
+		Threshold:          threshold,
+		Members:            members,
+		ThresholdPublicKey: keys.ThresholdPublicKey,
+		OnRecovered:        onRecovered,
+	})
+	require.NoError(t, err)
+	return session, keys, msg
+}
+
+func TestSigningSessionConcurrent(t *testing.T) {
+	const n, threshold = 10, 6
+	recovered := make(chan []byte, n)
+	session, keys, msg := newTestSigningSession(context.Background(), t, n, threshold, func(sig []byte) {
+		recovered <- sig
+	})
+
+	// every member sends its share twice, concurrently
+	var (
+		wg      sync.WaitGroup
+		mtx     sync.Mutex
+		results = make(map[error]int)
+	)
+	for _, share := range keys.Shares {
+		sig := mustSign(t, share.PrivKey, msg)
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func(proTxHash crypto.ProTxHash) {
+				defer wg.Done()
+				err := session.AddShare(proTxHash, sig)
+				switch {
+				case errors.Is(err, ErrDuplicateSignatureShare):
+					err = ErrDuplicateSignatureShare
+				case errors.Is(err, ErrSigningSessionClosed):
+					err = ErrSigningSessionClosed
+				}
+				mtx.Lock()
+				results[err]++
+				mtx.Unlock()
+			}(share.ProTxHash)
+		}
+	}
+	wg.Wait()
+
+	assert.Equal(t, threshold, results[nil])
+	assert.Equal(t, 2*n, results[nil]+results[ErrDuplicateSignatureShare]+results[ErrSigningSessionClosed], results)
+
+	<-session.Done()
+	sig, err := session.Result()
+	require.NoError(t, err)
+	assert.True(t, keys.ThresholdPublicKey.VerifySignatureDigest(msg, sig))
+	require.Len(t, recovered, 1)
+	assert.Equal(t, sig, <-recovered)
+
+	waited, err := session.Wait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, sig, waited)
+}
+
+func TestSigningSessionDropsShares(t *testing.T) {
+	session, keys, msg := newTestSigningSession(context.Background(), t, 4, 3, nil)
+	share := keys.Shares[0]
+
+	err := session.AddShare(crypto.RandProTxHash(), mustSign(t, share.PrivKey, msg))
+	assert.ErrorIs(t, err, errUnknownMember)
+
+	// a share of another member or message is invalid, and the member can
+	// still send its valid share afterwards
+	err = session.AddShare(share.ProTxHash, mustSign(t, keys.Shares[1].PrivKey, msg))
+	assert.ErrorIs(t, err, ErrInvalidSignatureShare)
+	err = session.AddShare(share.ProTxHash, mustSign(t, share.PrivKey, crypto.CRandBytes(32)))
+	assert.ErrorIs(t, err, ErrInvalidSignatureShare)
+	err = session.AddShare(share.ProTxHash, []byte{1, 2, 3})
+	assert.ErrorIs(t, err, ErrInvalidSignatureShare)
+	require.NoError(t, session.AddShare(share.ProTxHash, mustSign(t, share.PrivKey, msg)))
+
+	err = session.AddShare(share.ProTxHash, mustSign(t, share.PrivKey, msg))
+	assert.ErrorIs(t, err, ErrDuplicateSignatureShare)
+
+	_, err = session.Result()
+	assert.ErrorIs(t, err, ErrNotEnoughSignatureShares)
+	select {
+	case <-session.Done():
+		t.Fatal("session ended with one share")
+	default:
+	}
+}
+
+func TestSigningSessionCancel(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	session, keys, msg := newTestSigningSession(ctx, t, 4, 3, func([]byte) {
+		t.Error("cancelled session recovered a signature")
+	})
+	require.NoError(t, session.AddShare(keys.Shares[0].ProTxHash, mustSign(t, keys.Shares[0].PrivKey, msg)))
+
+	cause := errors.New("request expired")
+	cancel(cause)
+	<-session.Done()
+	_, err := session.Result()
+	assert.ErrorIs(t, err, cause)
+	err = session.AddShare(keys.Shares[1].ProTxHash, mustSign(t, keys.Shares[1].PrivKey, msg))
+	assert.ErrorIs(t, err, ErrSigningSessionClosed)
+
+	session, _, _ = newTestSigningSession(context.Background(), t, 4, 3, nil)
+	session.Cancel()
+	session.Cancel()
+	_, err = session.Wait(context.Background())
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// waiting does not cancel the session
+	session, _, _ = newTestSigningSession(context.Background(), t, 4, 3, nil)
+	waitCtx, waitCancel := context.WithCancel(context.Background())
+	waitCancel()
+	_, err = session.Wait(waitCtx)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = session.Result()
+	assert.ErrorIs(t, err, ErrNotEnoughSignatureShares)
+}
+
+func TestSigningSessionThresholdPublicKey(t *testing.T) {
+	keys, err := GenerateQuorumKeyShares(2, crypto.RandProTxHashes(3))
+	require.NoError(t, err)
+	members := make([]SigningMember, len(keys.Shares))
+	for i, share := range keys.Shares {
+		members[i] = SigningMember{ProTxHash: share.ProTxHash, PubKeyShare: share.PubKey}
+	}
+	msg := crypto.CRandBytes(32)
+	session, err := NewSigningSession(context.Background(), SigningSessionParams{
+		QuorumHash:         crypto.RandQuorumHash(),
+		RequestID:          []byte{1},
+		Msg:                msg,
+		Threshold:          2,
+		Members:            members,
+		ThresholdPublicKey: GenPrivKey().PubKey(),
+	})
+	require.NoError(t, err)
+	for _, share := range keys.Shares[:2] {
+		err = session.AddShare(share.ProTxHash, mustSign(t, share.PrivKey, msg))
+	}
+	assert.Error(t, err)
+	<-session.Done()
+	sig, resultErr := session.Result()
+	assert.Nil(t, sig)
+	assert.Equal(t, err, resultErr)
+}
+
+func TestNewSigningSessionInvalid(t *testing.T) {
+	member := SigningMember{ProTxHash: crypto.RandProTxHash(), PubKeyShare: GenPrivKey().PubKey()}
+	valid := SigningSessionParams{
+		QuorumHash: crypto.RandQuorumHash(),
+		RequestID:  []byte{1},
+		Threshold:  1,
+		Members:    []SigningMember{member},
+	}
+	_, err := NewSigningSession(context.Background(), valid)
+	require.NoError(t, err)
+
+	testCases := map[string]func(p *SigningSessionParams){
+		"quorum hash": func(p *SigningSessionParams) { p.QuorumHash = p.QuorumHash[1:] },
+		"request id":  func(p *SigningSessionParams) { p.RequestID = nil },
+		"threshold":   func(p *SigningSessionParams) { p.Threshold = 2 },
+		"no members":  func(p *SigningSessionParams) { p.Members = nil },
+		"duplicate":   func(p *SigningSessionParams) { p.Members = []SigningMember{member, member} },
+		"proTxHash": func(p *SigningSessionParams) {
+			p.Members = []SigningMember{{ProTxHash: member.ProTxHash[1:], PubKeyShare: member.PubKeyShare}}
+		},
+		"pubkey": func(p *SigningSessionParams) {
+			p.Members = []SigningMember{{ProTxHash: member.ProTxHash, PubKeyShare: PubKey(make([]byte, PubKeySize))}}
+		},
+	}
+	for name, modify := range testCases {
+		params := valid
+		modify(&params)
+		_, err := NewSigningSession(context.Background(), params)
+		assert.Error(t, err, name)
+	}
+}
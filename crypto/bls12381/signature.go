@@ -0,0 +1,77 @@
+package bls12381
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/dashpay/tenderdash/internal/jsontypes"
+)
+
+// SignatureName is the JSON type tag of Signature.
+const SignatureName = "tendermint/SignatureBLS12381"
+
+var (
+	errSignatureInvalidSize = errors.New("invalid signature size")
+	errSignatureInvalid     = errors.New("signature is not a valid G2 point")
+	errSignatureInfinity    = errors.New("signature is the point at infinity")
+)
+
+func init() {
+	jsontypes.MustRegister(Signature{})
+}
+
+// Signature is a BLS12-381 signature: a compressed point of G2.
+type Signature []byte
+
+// TypeTag satisfies the jsontypes.Tagged interface.
+func (Signature) TypeTag() string { return SignatureName }
+
+// Bytes returns the signature byte format.
+func (sig Signature) Bytes() []byte {
+	return sig
+}
+
+// Validate checks that the signature is the encoding of a point of the G2
+// subgroup other than the point at infinity.
+func (sig Signature) Validate() error {
+	if len(sig) != SignatureSize {
+		return fmt.Errorf("signature has wrong size %d: %w", len(sig), errSignatureInvalidSize)
+	}
+	if isInfinity(sig) {
+		return errSignatureInfinity
+	}
+	if err := defaultBackend.validateSignature(sig); err != nil {
+		return fmt.Errorf("%w: %v", errSignatureInvalid, err)
+	}
+	return nil
+}
+
+// Equals reports whether both signatures are the same.
+func (sig Signature) Equals(other Signature) bool {
+	return bytes.Equal(sig, other)
+}
+
+func (sig Signature) String() string {
+	return fmt.Sprintf("SignatureBLS12381{%X}", []byte(sig))
+}
+
+// HexString returns hex-string representation of signature
+func (sig Signature) HexString() string {
+	return hex.EncodeToString(sig)
+}
+
+// isInfinity reports whether b is the compressed encoding of the point at
+// infinity, which has only the compression and infinity flags set.
+func isInfinity(b []byte) bool {
+	if len(b) == 0 || b[0] != 0xc0 {
+		return false
+	}
+	for _, v := range b[1:] {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
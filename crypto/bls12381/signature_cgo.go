@@ -0,0 +1,32 @@
+//go:build cgo && !purego
+
+package bls12381
+
+import (
+	"fmt"
+
+	bls "github.com/dashpay/bls-signatures/go-bindings"
+)
+
+// G2Element is a point of G2 of the backend of the build: the G2Element of
+// dashpay/bls-signatures in cgo builds, and the PointG2 of kilic/bls12-381
+// otherwise.
+type G2Element = bls.G2Element
+
+// SignatureFromG2Element returns the signature of the given G2 point.
+func SignatureFromG2Element(el *G2Element) Signature {
+	return el.Serialize()
+}
+
+// G2Element decodes the signature into a G2 point, checking that it belongs to
+// the G2 subgroup.
+func (sig Signature) G2Element() (*G2Element, error) {
+	if len(sig) != SignatureSize {
+		return nil, fmt.Errorf("signature has wrong size %d: %w", len(sig), errSignatureInvalidSize)
+	}
+	el, err := bls.G2ElementFromBytes(sig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errSignatureInvalid, err)
+	}
+	return el, nil
+}
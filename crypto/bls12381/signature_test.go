@@ -0,0 +1,68 @@
+// nolint:lll
+package bls12381
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/internal/jsontypes"
+)
+
+func TestSignatureValidate(t *testing.T) {
+	sig := Signature(mustSign(t, GenPrivKey(), []byte("message")))
+	require.NoError(t, sig.Validate())
+
+	testCases := []struct {
+		name string
+		sig  Signature
+		err  error
+	}{
+		{name: "empty", sig: nil, err: errSignatureInvalidSize},
+		{name: "short", sig: sig[1:], err: errSignatureInvalidSize},
+		{name: "infinity", sig: mustHexToBytes("c00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"), err: errSignatureInfinity},
+		{name: "infinity not all zeros", sig: mustHexToBytes("c00000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"), err: errSignatureInvalid},
+		{name: "uncompressed tag", sig: mustHexToBytes("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaaa000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"), err: errSignatureInvalid},
+		{name: "x equal to p", sig: mustHexToBytes("9a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"), err: errSignatureInvalid},
+		{name: "not in subgroup", sig: mustHexToBytes("800000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002"), err: errSignatureInvalid},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorIs(t, tc.sig.Validate(), tc.err)
+		})
+	}
+}
+
+func TestSignatureEquals(t *testing.T) {
+	sig := Signature(mustSign(t, GenPrivKey(), []byte("message")))
+	assert.True(t, sig.Equals(append(Signature(nil), sig...)))
+	assert.False(t, sig.Equals(Signature(mustSign(t, GenPrivKey(), []byte("message")))))
+}
+
+func TestSignatureG2Element(t *testing.T) {
+	sig := Signature(mustSign(t, GenPrivKey(), []byte("message")))
+	el, err := sig.G2Element()
+	require.NoError(t, err)
+	assert.True(t, sig.Equals(SignatureFromG2Element(el)))
+
+	_, err = sig[1:].G2Element()
+	assert.ErrorIs(t, err, errSignatureInvalidSize)
+	notInSubgroup := mustHexToBytes("800000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000002")
+	_, err = Signature(notInSubgroup).G2Element()
+	assert.ErrorIs(t, err, errSignatureInvalid)
+}
+
+func TestSignatureJSON(t *testing.T) {
+	sig := Signature(mustSign(t, GenPrivKey(), []byte("message")))
+	data, err := jsontypes.Marshal(sig)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), SignatureName)
+
+	var decoded Signature
+	require.NoError(t, jsontypes.Unmarshal(data, &decoded))
+	assert.Equal(t, sig, decoded)
+	assert.Equal(t, "SignatureBLS12381{"+strings.ToUpper(sig.HexString())+"}", sig.String())
+}
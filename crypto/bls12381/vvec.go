@@ -0,0 +1,93 @@
+package bls12381
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/dashpay/tenderdash/internal/jsontypes"
+)
+
+// VerificationVectorName is the JSON type tag of VerificationVector.
+const VerificationVectorName = "tendermint/VerificationVectorBLS12381"
+
+var errEmptyVerificationVector = errors.New("empty verification vector")
+
+func init() {
+	jsontypes.MustRegister(VerificationVector{})
+}
+
+// VerificationVector holds the G1 commitments to the coefficients of the secret
+// polynomial of a threshold key, lowest degree first. It is public: anyone
+// holding it can derive the public key share of every quorum member and the
+// threshold public key, which is its first item.
+type VerificationVector []PubKey
+
+// TypeTag satisfies the jsontypes.Tagged interface.
+func (VerificationVector) TypeTag() string { return VerificationVectorName }
+
+// Threshold returns the number of shares needed to recover the threshold key.
+func (v VerificationVector) Threshold() int {
+	return len(v)
+}
+
+// ThresholdPublicKey returns the threshold public key of the quorum.
+func (v VerificationVector) ThresholdPublicKey() (PubKey, error) {
+	if len(v) == 0 {
+		return nil, errEmptyVerificationVector
+	}
+	return v[0], nil
+}
+
+// PubKeyShare returns the public key share of the quorum member with the given
+// BLS id.
+func (v VerificationVector) PubKeyShare(blsID BLSID) (PubKey, error) {
+	if len(v) == 0 {
+		return nil, errEmptyVerificationVector
+	}
+	id := blsID.scalar()
+	if id.Sign() == 0 {
+		return nil, fmt.Errorf("blsID %s maps to zero", blsID)
+	}
+	return v.eval(id)
+}
+
+// VerifySignatureShare reports whether sig is the signature share of msg by the
+// quorum member with the given BLS id.
+func (v VerificationVector) VerifySignatureShare(blsID BLSID, msg []byte, sig []byte) bool {
+	pubKey, err := v.PubKeyShare(blsID)
+	if err != nil {
+		return false
+	}
+	return pubKey.VerifySignature(msg, sig)
+}
+
+// Validate checks that the verification vector is not empty and that each of
+// its items is a valid public key.
+func (v VerificationVector) Validate() error {
+	if len(v) == 0 {
+		return errEmptyVerificationVector
+	}
+	for i, pk := range v {
+		if err := pk.Validate(); err != nil {
+			return fmt.Errorf("verification vector item %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// eval evaluates the committed polynomial at x, which gives the public key of
+// the private key share at x.
+func (v VerificationVector) eval(x *big.Int) (PubKey, error) {
+	terms := make([][]byte, len(v))
+	power := big.NewInt(1)
+	for i, pk := range v {
+		term, err := defaultBackend.mulPubKey(pk, scalarToBytes(power))
+		if err != nil {
+			return nil, err
+		}
+		terms[i] = term
+		power.Mul(power, x).Mod(power, curveOrder)
+	}
+	return defaultBackend.aggregatePubKeys(terms)
+}
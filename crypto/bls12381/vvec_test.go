@@ -0,0 +1,66 @@
+package bls12381
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+	"github.com/dashpay/tenderdash/internal/jsontypes"
+)
+
+func TestVerificationVectorPubKeyShare(t *testing.T) {
+	keys, err := GenerateQuorumKeyShares(3, crypto.RandProTxHashes(5))
+	require.NoError(t, err)
+	vvec := keys.VerificationVector
+	require.NoError(t, vvec.Validate())
+	assert.Equal(t, 3, vvec.Threshold())
+
+	thresholdPublicKey, err := vvec.ThresholdPublicKey()
+	require.NoError(t, err)
+	assert.Equal(t, keys.ThresholdPublicKey, thresholdPublicKey)
+
+	msg := []byte("message")
+	for _, share := range keys.Shares {
+		pubKey, err := vvec.PubKeyShare(mustBLSID(t, share.ProTxHash))
+		require.NoError(t, err)
+		assert.Equal(t, share.PubKey, pubKey)
+
+		sig := mustSign(t, share.PrivKey, msg)
+		assert.True(t, vvec.VerifySignatureShare(mustBLSID(t, share.ProTxHash), msg, sig))
+		assert.False(t, vvec.VerifySignatureShare(mustBLSID(t, crypto.RandProTxHash()), msg, sig))
+		// the proTxHash read in the wrong byte order is another member
+		wrongOrder, err := BLSIDFromInternalBytes(share.ProTxHash)
+		require.NoError(t, err)
+		assert.False(t, vvec.VerifySignatureShare(wrongOrder, msg, sig))
+	}
+
+	_, err = vvec.PubKeyShare(BLSID{})
+	assert.Error(t, err)
+	_, err = VerificationVector{}.PubKeyShare(mustBLSID(t, keys.Shares[0].ProTxHash))
+	assert.Error(t, err)
+	_, err = VerificationVector{}.ThresholdPublicKey()
+	assert.Error(t, err)
+	assert.Error(t, VerificationVector{}.Validate())
+	assert.Error(t, VerificationVector{vvec[0], PubKey(emptyPubKeyVal)}.Validate())
+}
+
+func TestVerificationVectorJSON(t *testing.T) {
+	keys, err := GenerateQuorumKeyShares(2, crypto.RandProTxHashes(3))
+	require.NoError(t, err)
+
+	data, err := jsontypes.Marshal(keys.VerificationVector)
+	require.NoError(t, err)
+	var wrapper struct {
+		Type  string          `json:"type"`
+		Value json.RawMessage `json:"value"`
+	}
+	require.NoError(t, json.Unmarshal(data, &wrapper))
+	assert.Equal(t, VerificationVectorName, wrapper.Type)
+
+	var decoded VerificationVector
+	require.NoError(t, jsontypes.Unmarshal(data, &decoded))
+	assert.Equal(t, keys.VerificationVector, decoded)
+}
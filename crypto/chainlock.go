@@ -0,0 +1,66 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/dashpay/dashd-go/btcjson"
+
+	tmbytes "github.com/dashpay/tenderdash/libs/bytes"
+)
+
+const (
+	// ChainLockRequestIDPrefix is the prefix of the request ids of chain locks.
+	ChainLockRequestIDPrefix = "clsig"
+	// LLMQSignatureSize is the size of the quorum signatures of dashd messages.
+	LLMQSignatureSize = 96
+)
+
+// ChainLock is the clsig message of dashd, defined by DIP-0008, which locks
+// the block of a height. Hashes are in the byte order dashd displays them in.
+type ChainLock struct {
+	Height    int32
+	BlockHash tmbytes.HexBytes
+	Signature tmbytes.HexBytes
+}
+
+// ParseChainLock deserializes a clsig message.
+func ParseChainLock(b []byte) (*ChainLock, error) {
+	r := wireReader{b: b}
+	cl := &ChainLock{
+		Height:    int32(r.uint32("height")),
+		BlockHash: r.hash("block hash"),
+		Signature: r.bytes(LLMQSignatureSize, "signature"),
+	}
+	if err := r.finish(); err != nil {
+		return nil, err
+	}
+	return cl, nil
+}
+
+// Bytes serializes the chain lock as a clsig message.
+func (cl *ChainLock) Bytes() []byte {
+	var buf bytes.Buffer
+	buf.Write(cl.heightBytes())
+	buf.Write(tmbytes.Reverse(cl.BlockHash))
+	buf.Write(cl.Signature)
+	return buf.Bytes()
+}
+
+// RequestID returns the id of the signing request of the chain lock.
+func (cl *ChainLock) RequestID() []byte {
+	return LLMQRequestID(ChainLockRequestIDPrefix, cl.heightBytes())
+}
+
+// Verify reports whether the chain lock is signed by the quorum of the given
+// type and hash, whose threshold public key is pubKey. The quorum is the one
+// SelectQuorumForSigning selects for the request id of the chain lock.
+func (cl *ChainLock) Verify(llmqType btcjson.LLMQType, quorumHash QuorumHash, pubKey PubKey) bool {
+	return VerifyLLMQSignature(pubKey, llmqType, quorumHash, cl.RequestID(), cl.BlockHash, cl.Signature)
+}
+
+func (cl *ChainLock) heightBytes() []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, uint32(cl.Height))
+	return b
+}
@@ -0,0 +1,124 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/dashpay/dashd-go/btcjson"
+
+	tmbytes "github.com/dashpay/tenderdash/libs/bytes"
+)
+
+const (
+	// InstantSendLockRequestIDPrefix is the prefix of the request ids of
+	// InstantSend locks. dashd uses it for deterministic locks too: "isdlock"
+	// only names their network message.
+	InstantSendLockRequestIDPrefix = "islock"
+
+	// outPointSize is the serialized size of an outpoint.
+	outPointSize = DefaultHashSize + 4
+)
+
+// OutPoint is a transaction output spent by a locked transaction.
+type OutPoint struct {
+	TxID  tmbytes.HexBytes
+	Index uint32
+}
+
+// InstantSendLock is the islock message of dashd, defined by DIP-0010, or its
+// deterministic isdlock variant, defined by DIP-0022, which locks the inputs
+// of a transaction. Hashes are in the byte order dashd displays them in.
+type InstantSendLock struct {
+	// Version is 0 for islock messages, which have no version.
+	Version uint8
+	Inputs  []OutPoint
+	TxID    tmbytes.HexBytes
+	// CycleHash is the hash of the block that starts the DKG cycle of the
+	// signing quorum. It is only set for isdlock messages.
+	CycleHash tmbytes.HexBytes
+	Signature tmbytes.HexBytes
+}
+
+// ParseInstantSendLock deserializes an islock message.
+func ParseInstantSendLock(b []byte) (*InstantSendLock, error) {
+	return parseInstantSendLock(b, false)
+}
+
+// ParseInstantSendDeterministicLock deserializes an isdlock message.
+func ParseInstantSendDeterministicLock(b []byte) (*InstantSendLock, error) {
+	return parseInstantSendLock(b, true)
+}
+
+func parseInstantSendLock(b []byte, deterministic bool) (*InstantSendLock, error) {
+	r := wireReader{b: b}
+	isLock := &InstantSendLock{}
+	if deterministic {
+		isLock.Version = r.uint8("version")
+		if r.err == nil && isLock.Version == 0 {
+			r.fail("version 0 of a deterministic lock")
+		}
+	}
+	isLock.Inputs = make([]OutPoint, r.compactSize(outPointSize, "inputs"))
+	for i := range isLock.Inputs {
+		isLock.Inputs[i] = OutPoint{
+			TxID:  r.hash("input txid"),
+			Index: r.uint32("input index"),
+		}
+	}
+	isLock.TxID = r.hash("txid")
+	if deterministic {
+		isLock.CycleHash = r.hash("cycle hash")
+	}
+	isLock.Signature = r.bytes(LLMQSignatureSize, "signature")
+	if err := r.finish(); err != nil {
+		return nil, err
+	}
+	return isLock, nil
+}
+
+// IsDeterministic reports whether the lock is an isdlock message.
+func (l *InstantSendLock) IsDeterministic() bool {
+	return l.Version != 0
+}
+
+// Bytes serializes the lock as an islock or isdlock message.
+func (l *InstantSendLock) Bytes() []byte {
+	var buf bytes.Buffer
+	if l.IsDeterministic() {
+		buf.WriteByte(l.Version)
+	}
+	buf.Write(l.inputsBytes())
+	buf.Write(tmbytes.Reverse(l.TxID))
+	if l.IsDeterministic() {
+		buf.Write(tmbytes.Reverse(l.CycleHash))
+	}
+	buf.Write(l.Signature)
+	return buf.Bytes()
+}
+
+// RequestID returns the id of the signing request of the lock, which only
+// depends on its inputs, whatever the version of the lock.
+func (l *InstantSendLock) RequestID() []byte {
+	return LLMQRequestID(InstantSendLockRequestIDPrefix, l.inputsBytes())
+}
+
+// Verify reports whether the lock is signed by the quorum of the given type
+// and hash, whose threshold public key is pubKey. The quorum is the one
+// SelectQuorumForSigning selects for the request id of the lock, among the
+// quorums of the cycle of CycleHash for deterministic locks.
+func (l *InstantSendLock) Verify(llmqType btcjson.LLMQType, quorumHash QuorumHash, pubKey PubKey) bool {
+	return VerifyLLMQSignature(pubKey, llmqType, quorumHash, l.RequestID(), l.TxID, l.Signature)
+}
+
+// inputsBytes serializes the inputs as a vector of outpoints.
+func (l *InstantSendLock) inputsBytes() []byte {
+	var buf bytes.Buffer
+	buf.Write(compactSize(uint64(len(l.Inputs))))
+	index := make([]byte, 4)
+	for _, input := range l.Inputs {
+		buf.Write(tmbytes.Reverse(input.TxID))
+		binary.LittleEndian.PutUint32(index, input.Index)
+		buf.Write(index)
+	}
+	return buf.Bytes()
+}
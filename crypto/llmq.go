@@ -0,0 +1,204 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/dashpay/dashd-go/btcjson"
+)
+
+var (
+	// ErrUnknownLLMQType is returned for quorum types without parameters.
+	ErrUnknownLLMQType = errors.New("unknown llmq type")
+	// ErrInvalidLLMQParams is returned for inconsistent quorum parameters.
+	ErrInvalidLLMQParams = errors.New("invalid llmq params")
+)
+
+// LLMQParams are the consensus parameters of a long living masternode quorum
+// type, as defined by DIP-0006 and dashd.
+type LLMQParams struct {
+	Type btcjson.LLMQType
+	Name string
+	// Size is the number of members of a quorum.
+	Size int
+	// MinSize is the number of valid members a DKG needs to create a quorum.
+	MinSize int
+	// Threshold is the number of signature shares that recover a threshold
+	// signature.
+	Threshold int
+	// DKGInterval is the number of blocks between two DKGs.
+	DKGInterval int
+	// SigningActiveQuorumCount is the number of the most recent quorums that
+	// sign requests.
+	SigningActiveQuorumCount int
+	// UseRotation reports whether quorums rotate members per DIP-0024.
+	UseRotation bool
+}
+
+// Validate checks that the parameters are consistent.
+func (p LLMQParams) Validate() error {
+	switch {
+	case p.Name == "":
+		return fmt.Errorf("%w: type %d has no name", ErrInvalidLLMQParams, p.Type)
+	case p.Size < 1:
+		return fmt.Errorf("%w: %s: size %d", ErrInvalidLLMQParams, p.Name, p.Size)
+	case p.MinSize < 1 || p.MinSize > p.Size:
+		return fmt.Errorf("%w: %s: min size %d is out of range [1, %d]", ErrInvalidLLMQParams, p.Name, p.MinSize, p.Size)
+	case p.Threshold < 1 || p.Threshold > p.MinSize:
+		return fmt.Errorf("%w: %s: threshold %d is out of range [1, %d]", ErrInvalidLLMQParams, p.Name, p.Threshold, p.MinSize)
+	case p.DKGInterval < 1:
+		return fmt.Errorf("%w: %s: dkg interval %d", ErrInvalidLLMQParams, p.Name, p.DKGInterval)
+	case p.SigningActiveQuorumCount < 1:
+		return fmt.Errorf("%w: %s: signing active quorum count %d", ErrInvalidLLMQParams, p.Name, p.SigningActiveQuorumCount)
+	}
+	return nil
+}
+
+// builtinLLMQParams are the quorum types of dashd, from llmq/params.h.
+var builtinLLMQParams = []LLMQParams{
+	{
+		Type: btcjson.LLMQType_50_60, Name: "llmq_50_60",
+		Size: 50, MinSize: 40, Threshold: 30,
+		DKGInterval: 24, SigningActiveQuorumCount: 24,
+	},
+	{
+		Type: btcjson.LLMQType_400_60, Name: "llmq_400_60",
+		Size: 400, MinSize: 300, Threshold: 240,
+		DKGInterval: 24 * 12, SigningActiveQuorumCount: 4,
+	},
+	{
+		Type: btcjson.LLMQType_400_85, Name: "llmq_400_85",
+		Size: 400, MinSize: 350, Threshold: 340,
+		DKGInterval: 24 * 24, SigningActiveQuorumCount: 4,
+	},
+	{
+		Type: btcjson.LLMQType_100_67, Name: "llmq_100_67",
+		Size: 100, MinSize: 80, Threshold: 67,
+		DKGInterval: 24, SigningActiveQuorumCount: 24,
+	},
+	{
+		Type: btcjson.LLMQType_60_75, Name: "llmq_60_75",
+		Size: 60, MinSize: 50, Threshold: 45,
+		DKGInterval: 24 * 12, SigningActiveQuorumCount: 32, UseRotation: true,
+	},
+	{
+		Type: btcjson.LLMQType_25_67, Name: "llmq_25_67",
+		Size: 25, MinSize: 22, Threshold: 17,
+		DKGInterval: 24, SigningActiveQuorumCount: 24,
+	},
+	{
+		Type: btcjson.LLMQType_TEST, Name: "llmq_test",
+		Size: 3, MinSize: 2, Threshold: 2,
+		DKGInterval: 24, SigningActiveQuorumCount: 2,
+	},
+	{
+		Type: btcjson.LLMQType_DEVNET, Name: "llmq_devnet",
+		Size: 12, MinSize: 7, Threshold: 6,
+		DKGInterval: 24, SigningActiveQuorumCount: 4,
+	},
+	{
+		Type: btcjson.LLMQType_TEST_V17, Name: "llmq_test_v17",
+		Size: 3, MinSize: 2, Threshold: 2,
+		DKGInterval: 24, SigningActiveQuorumCount: 2,
+	},
+	{
+		Type: btcjson.LLMQType_TEST_DIP0024, Name: "llmq_test_dip0024",
+		Size: 4, MinSize: 4, Threshold: 2,
+		DKGInterval: 24, SigningActiveQuorumCount: 2, UseRotation: true,
+	},
+	{
+		Type: btcjson.LLMQType_TEST_INSTANTSEND, Name: "llmq_test_instantsend",
+		Size: 3, MinSize: 2, Threshold: 2,
+		DKGInterval: 24, SigningActiveQuorumCount: 2,
+	},
+	{
+		Type: btcjson.LLMQType_DEVNET_DIP0024, Name: "llmq_devnet_dip0024",
+		Size: 8, MinSize: 6, Threshold: 4,
+		DKGInterval: 48, SigningActiveQuorumCount: 2, UseRotation: true,
+	},
+	{
+		Type: btcjson.LLMQType_TEST_PLATFORM, Name: "llmq_test_platform",
+		Size: 3, MinSize: 2, Threshold: 2,
+		DKGInterval: 24, SigningActiveQuorumCount: 2,
+	},
+	{
+		Type: btcjson.LLMQType_DEVNET_PLATFORM, Name: "llmq_devnet_platform",
+		Size: 12, MinSize: 9, Threshold: 8,
+		DKGInterval: 24, SigningActiveQuorumCount: 4,
+	},
+}
+
+// llmqRegistry holds the parameters of every known quorum type.
+var llmqRegistry = newLLMQParamsRegistry(builtinLLMQParams)
+
+type llmqParamsRegistry struct {
+	mtx    sync.RWMutex
+	params map[btcjson.LLMQType]LLMQParams
+}
+
+func newLLMQParamsRegistry(params []LLMQParams) *llmqParamsRegistry {
+	r := &llmqParamsRegistry{params: make(map[btcjson.LLMQType]LLMQParams, len(params))}
+	for _, p := range params {
+		r.params[p.Type] = p
+	}
+	return r
+}
+
+// GetLLMQParams returns the parameters of a quorum type.
+func GetLLMQParams(llmqType btcjson.LLMQType) (LLMQParams, error) {
+	llmqRegistry.mtx.RLock()
+	defer llmqRegistry.mtx.RUnlock()
+	p, ok := llmqRegistry.params[llmqType]
+	if !ok {
+		return LLMQParams{}, fmt.Errorf("%w: %d", ErrUnknownLLMQType, llmqType)
+	}
+	return p, nil
+}
+
+// LLMQTypes returns the known quorum types in ascending order.
+func LLMQTypes() []btcjson.LLMQType {
+	llmqRegistry.mtx.RLock()
+	defer llmqRegistry.mtx.RUnlock()
+	types := make([]btcjson.LLMQType, 0, len(llmqRegistry.params))
+	for llmqType := range llmqRegistry.params {
+		types = append(types, llmqType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// RegisterLLMQParams adds the parameters of a custom quorum type, or replaces
+// those of a devnet or regtest type, such as dashd's -llmqdevnetparams and
+// -llmqtestparams do. The quorum types of mainnet and testnet cannot be
+// replaced.
+func RegisterLLMQParams(params LLMQParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	if params.Type >= btcjson.LLMQType_50_60 && params.Type <= btcjson.LLMQType_25_67 {
+		return fmt.Errorf("%w: %s is a mainnet quorum type", ErrInvalidLLMQParams, params.Type.Name())
+	}
+	llmqRegistry.mtx.Lock()
+	defer llmqRegistry.mtx.Unlock()
+	llmqRegistry.params[params.Type] = params
+	return nil
+}
+
+// ThresholdFor returns the number of signature shares that recover a threshold
+// signature of a quorum of the given type.
+func ThresholdFor(llmqType btcjson.LLMQType) (int, error) {
+	p, err := GetLLMQParams(llmqType)
+	if err != nil {
+		return 0, err
+	}
+	return p.Threshold, nil
+}
+
+// IsThresholdReached reports whether n signature shares recover a threshold
+// signature of a quorum of the given type. It is false for unknown types.
+func IsThresholdReached(llmqType btcjson.LLMQType, n int) bool {
+	threshold, err := ThresholdFor(llmqType)
+	return err == nil && n >= threshold
+}
@@ -0,0 +1,107 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/dashpay/dashd-go/btcjson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinLLMQParams(t *testing.T) {
+	require.Len(t, LLMQTypes(), len(builtinLLMQParams))
+	for _, llmqType := range LLMQTypes() {
+		p, err := GetLLMQParams(llmqType)
+		require.NoError(t, err)
+		assert.NoError(t, p.Validate(), p.Name)
+		assert.NoError(t, llmqType.Validate(), p.Name)
+		assert.Equal(t, llmqType.Name(), p.Name)
+		assert.Equal(t, llmqType, btcjson.GetLLMQType(p.Name))
+	}
+
+	p, err := GetLLMQParams(SmallQuorumType())
+	require.NoError(t, err)
+	assert.Equal(t, 3, p.Size)
+	assert.Equal(t, 2, p.Threshold)
+
+	p, err = GetLLMQParams(btcjson.LLMQType_60_75)
+	require.NoError(t, err)
+	assert.True(t, p.UseRotation)
+
+	_, err = GetLLMQParams(btcjson.LLMQType(0))
+	assert.ErrorIs(t, err, ErrUnknownLLMQType)
+}
+
+func TestThresholdFor(t *testing.T) {
+	testCases := []struct {
+		llmqType  btcjson.LLMQType
+		threshold int
+	}{
+		{btcjson.LLMQType_50_60, 30},
+		{btcjson.LLMQType_400_60, 240},
+		{btcjson.LLMQType_400_85, 340},
+		{btcjson.LLMQType_100_67, 67},
+		{btcjson.LLMQType_60_75, 45},
+		{btcjson.LLMQType_25_67, 17},
+		{btcjson.LLMQType_TEST, 2},
+		{btcjson.LLMQType_DEVNET, 6},
+		{btcjson.LLMQType_DEVNET_DIP0024, 4},
+		{btcjson.LLMQType_DEVNET_PLATFORM, 8},
+	}
+	for _, tc := range testCases {
+		threshold, err := ThresholdFor(tc.llmqType)
+		require.NoError(t, err)
+		assert.Equal(t, tc.threshold, threshold, tc.llmqType.Name())
+		assert.False(t, IsThresholdReached(tc.llmqType, tc.threshold-1))
+		assert.True(t, IsThresholdReached(tc.llmqType, tc.threshold))
+	}
+
+	_, err := ThresholdFor(btcjson.LLMQType(0))
+	assert.ErrorIs(t, err, ErrUnknownLLMQType)
+	assert.False(t, IsThresholdReached(btcjson.LLMQType(0), 1000))
+}
+
+func TestRegisterLLMQParams(t *testing.T) {
+	devnet, err := GetLLMQParams(btcjson.LLMQType_DEVNET)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		llmqRegistry = newLLMQParamsRegistry(builtinLLMQParams)
+	})
+
+	// like -llmqdevnetparams=5:3
+	custom := devnet
+	custom.Size, custom.MinSize, custom.Threshold = 5, 3, 3
+	require.NoError(t, RegisterLLMQParams(custom))
+	threshold, err := ThresholdFor(btcjson.LLMQType_DEVNET)
+	require.NoError(t, err)
+	assert.Equal(t, 3, threshold)
+
+	regtest := LLMQParams{
+		Type: 200, Name: "llmq_regtest_custom",
+		Size: 5, MinSize: 4, Threshold: 3,
+		DKGInterval: 24, SigningActiveQuorumCount: 2,
+	}
+	require.NoError(t, RegisterLLMQParams(regtest))
+	p, err := GetLLMQParams(200)
+	require.NoError(t, err)
+	assert.Equal(t, regtest, p)
+	assert.Contains(t, LLMQTypes(), btcjson.LLMQType(200))
+
+	mainnet, err := GetLLMQParams(btcjson.LLMQType_400_60)
+	require.NoError(t, err)
+	mainnet.Threshold = 1
+	assert.ErrorIs(t, RegisterLLMQParams(mainnet), ErrInvalidLLMQParams)
+
+	for name, modify := range map[string]func(p *LLMQParams){
+		"name":         func(p *LLMQParams) { p.Name = "" },
+		"size":         func(p *LLMQParams) { p.Size = 0 },
+		"min size":     func(p *LLMQParams) { p.MinSize = p.Size + 1 },
+		"threshold":    func(p *LLMQParams) { p.Threshold = p.MinSize + 1 },
+		"dkg interval": func(p *LLMQParams) { p.DKGInterval = 0 },
+		"active":       func(p *LLMQParams) { p.SigningActiveQuorumCount = 0 },
+	} {
+		invalid := regtest
+		modify(&invalid)
+		assert.ErrorIs(t, RegisterLLMQParams(invalid), ErrInvalidLLMQParams, name)
+	}
+}
@@ -0,0 +1,168 @@
+package crypto_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/dashpay/dashd-go/btcjson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+	"github.com/dashpay/tenderdash/crypto/bls12381"
+	tmbytes "github.com/dashpay/tenderdash/libs/bytes"
+)
+
+// llmqMessageFixture is the format of testdata/llmq_messages.json.
+type llmqMessageFixture struct {
+	Name            string           `json:"name"`
+	Kind            string           `json:"kind"`
+	LLMQType        btcjson.LLMQType `json:"llmq_type"`
+	QuorumHash      string           `json:"quorum_hash"`
+	QuorumPublicKey string           `json:"quorum_public_key"`
+	Message         string           `json:"message"`
+	RequestID       string           `json:"request_id"`
+	SignHash        string           `json:"sign_hash"`
+}
+
+// llmqMessage is the common interface of parsed clsig, islock and isdlock
+// messages.
+type llmqMessage interface {
+	Bytes() []byte
+	RequestID() []byte
+	Verify(llmqType btcjson.LLMQType, quorumHash crypto.QuorumHash, pubKey crypto.PubKey) bool
+}
+
+var llmqMessageParsers = map[string]func([]byte) (llmqMessage, []byte, error){
+	"clsig": func(b []byte) (llmqMessage, []byte, error) {
+		cl, err := crypto.ParseChainLock(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		return cl, cl.BlockHash, nil
+	},
+	"islock": func(b []byte) (llmqMessage, []byte, error) {
+		isLock, err := crypto.ParseInstantSendLock(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		return isLock, isLock.TxID, nil
+	},
+	"isdlock": func(b []byte) (llmqMessage, []byte, error) {
+		isLock, err := crypto.ParseInstantSendDeterministicLock(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		return isLock, isLock.TxID, nil
+	},
+}
+
+func TestLLMQMessageFixtures(t *testing.T) {
+	data, err := os.ReadFile("testdata/llmq_messages.json")
+	require.NoError(t, err)
+	var fixtures []llmqMessageFixture
+	require.NoError(t, json.Unmarshal(data, &fixtures))
+	require.NotEmpty(t, fixtures)
+
+	for _, fx := range fixtures {
+		fx := fx
+		t.Run(fx.Name, func(t *testing.T) {
+			parse, ok := llmqMessageParsers[fx.Kind]
+			require.True(t, ok, fx.Kind)
+			raw := tmbytes.MustHexDecode(fx.Message)
+			quorumHash := crypto.QuorumHash(tmbytes.MustHexDecode(fx.QuorumHash))
+			pubKey, err := bls12381.ParsePubKey(tmbytes.MustHexDecode(fx.QuorumPublicKey))
+			require.NoError(t, err)
+
+			msg, msgHash, err := parse(raw)
+			require.NoError(t, err)
+			assert.Equal(t, raw, msg.Bytes())
+			assert.Equal(t, fx.RequestID, hex.EncodeToString(msg.RequestID()))
+			signHash, err := crypto.LLMQSignHash(fx.LLMQType, quorumHash, msg.RequestID(), msgHash)
+			require.NoError(t, err)
+			assert.Equal(t, fx.SignHash, hex.EncodeToString(signHash))
+
+			assert.True(t, msg.Verify(fx.LLMQType, quorumHash, pubKey))
+			assert.False(t, msg.Verify(btcjson.LLMQType_TEST, quorumHash, pubKey))
+			assert.False(t, msg.Verify(fx.LLMQType, crypto.RandQuorumHash(), pubKey))
+			assert.False(t, msg.Verify(fx.LLMQType, quorumHash, bls12381.GenPrivKey().PubKey()))
+
+			// any change of the signed fields breaks the signature, while the
+			// version and cycle hash of isdlock messages are not signed
+			for i := 0; i < len(raw)-crypto.LLMQSignatureSize; i++ {
+				tampered := append([]byte(nil), raw...)
+				tampered[i] ^= 1
+				changed, changedHash, err := parse(tampered)
+				if err != nil {
+					continue
+				}
+				signed := !bytes.Equal(msg.RequestID(), changed.RequestID()) ||
+					!bytes.Equal(msgHash, changedHash)
+				assert.Equal(t, !signed, changed.Verify(fx.LLMQType, quorumHash, pubKey), "byte %d", i)
+			}
+		})
+	}
+}
+
+func TestParseChainLock(t *testing.T) {
+	cl := &crypto.ChainLock{
+		Height:    -1,
+		BlockHash: crypto.RandQuorumHash(),
+		Signature: crypto.CRandBytes(crypto.LLMQSignatureSize),
+	}
+	raw := cl.Bytes()
+	require.Len(t, raw, 4+32+96)
+	parsed, err := crypto.ParseChainLock(raw)
+	require.NoError(t, err)
+	assert.Equal(t, cl, parsed)
+
+	for _, b := range [][]byte{nil, raw[:len(raw)-1], append(raw, 0)} {
+		_, err := crypto.ParseChainLock(b)
+		assert.ErrorIs(t, err, crypto.ErrInvalidWireMessage)
+	}
+}
+
+func TestParseInstantSendLock(t *testing.T) {
+	isLock := &crypto.InstantSendLock{
+		Inputs:    []crypto.OutPoint{{TxID: crypto.RandQuorumHash(), Index: 7}},
+		TxID:      crypto.RandQuorumHash(),
+		Signature: crypto.CRandBytes(crypto.LLMQSignatureSize),
+	}
+	raw := isLock.Bytes()
+	parsed, err := crypto.ParseInstantSendLock(raw)
+	require.NoError(t, err)
+	assert.Equal(t, isLock, parsed)
+	assert.False(t, parsed.IsDeterministic())
+	_, err = crypto.ParseInstantSendDeterministicLock(raw)
+	assert.ErrorIs(t, err, crypto.ErrInvalidWireMessage)
+
+	isdLock := *isLock
+	isdLock.Version = 1
+	isdLock.CycleHash = crypto.RandQuorumHash()
+	rawd := isdLock.Bytes()
+	parsed, err = crypto.ParseInstantSendDeterministicLock(rawd)
+	require.NoError(t, err)
+	assert.Equal(t, &isdLock, parsed)
+	assert.True(t, parsed.IsDeterministic())
+	// both kinds share the request id of their inputs
+	assert.Equal(t, isLock.RequestID(), isdLock.RequestID())
+
+	for name, b := range map[string][]byte{
+		"empty":         nil,
+		"truncated":     raw[:len(raw)-1],
+		"trailing":      append(append([]byte(nil), raw...), 0),
+		"too many":      append([]byte{0xfd, 0xff, 0xff}, raw[1:]...),
+		"non-canonical": append([]byte{0xfd, 0x01, 0x00}, raw[1:]...),
+		"version 0":     append([]byte{0}, raw...),
+	} {
+		parse := crypto.ParseInstantSendLock
+		if name == "version 0" {
+			parse = crypto.ParseInstantSendDeterministicLock
+		}
+		_, err := parse(b)
+		assert.ErrorIs(t, err, crypto.ErrInvalidWireMessage, name)
+	}
+}
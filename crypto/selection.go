@@ -0,0 +1,90 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/dashpay/dashd-go/btcjson"
+
+	tmbytes "github.com/dashpay/tenderdash/libs/bytes"
+)
+
+// ErrNoSigningQuorum is returned when no active quorum can sign a request.
+var ErrNoSigningQuorum = errors.New("no quorum to sign the request")
+
+// SelectQuorumForSigning returns the quorum of the given type that signs the
+// request with the given id, as CSigningManager::SelectQuorumForSigning of
+// dashd. Quorum hashes and the request id are in the byte order dashd displays
+// them in, see LLMQSignHash.
+//
+// quorums are the active quorums at the signing height, the most recent
+// first, as "quorum list" returns them. Only the first
+// SigningActiveQuorumCount of them are considered, and the one whose hash of
+// the type, the quorum hash and the request id is the lowest is selected.
+//
+// Quorum types with rotation (DIP-0024) select a quorum index from the bits
+// of the request id instead, so quorums[i] must be the active quorum with
+// quorum index i.
+func SelectQuorumForSigning(llmqType btcjson.LLMQType, quorums []QuorumHash, requestID []byte) (QuorumHash, error) {
+	params, err := GetLLMQParams(llmqType)
+	if err != nil {
+		return nil, err
+	}
+	if len(requestID) != DefaultHashSize {
+		return nil, fmt.Errorf("%w: request id has wrong size %d, expected %d",
+			ErrInvalidSignHashInput, len(requestID), DefaultHashSize)
+	}
+	if len(quorums) > params.SigningActiveQuorumCount {
+		quorums = quorums[:params.SigningActiveQuorumCount]
+	}
+	if len(quorums) == 0 {
+		return nil, fmt.Errorf("%w: no active %s quorums", ErrNoSigningQuorum, params.Name)
+	}
+	for _, quorumHash := range quorums {
+		if len(quorumHash) != QuorumHashSize {
+			return nil, fmt.Errorf("%w: quorum hash has wrong size %d, expected %d",
+				ErrInvalidSignHashInput, len(quorumHash), QuorumHashSize)
+		}
+	}
+	if params.UseRotation {
+		return selectRotatedQuorum(params, quorums, requestID)
+	}
+	return selectQuorumByScore(params, quorums, requestID), nil
+}
+
+// selectQuorumByScore returns the quorum with the lowest score, which dashd
+// compares as uint256 values, byte by byte in the order it serializes them.
+func selectQuorumByScore(params LLMQParams, quorums []QuorumHash, requestID []byte) QuorumHash {
+	id := tmbytes.Reverse(requestID)
+	var (
+		selected QuorumHash
+		lowest   []byte
+	)
+	for _, quorumHash := range quorums {
+		var buf bytes.Buffer
+		buf.WriteByte(byte(params.Type))
+		buf.Write(tmbytes.Reverse(quorumHash))
+		buf.Write(id)
+		score := Checksum(Checksum(buf.Bytes()))
+		if lowest == nil || bytes.Compare(score, lowest) < 0 {
+			selected, lowest = quorumHash, score
+		}
+	}
+	return selected
+}
+
+// selectRotatedQuorum returns the quorum whose index is taken from the bits of
+// the last 64 bits of the request id that dashd takes them from.
+func selectRotatedQuorum(params LLMQParams, quorums []QuorumHash, requestID []byte) (QuorumHash, error) {
+	n := bits.Len(uint(params.SigningActiveQuorumCount)) - 1
+	// selectionHash.GetUint64(3) reads the last 8 bytes in serialization order
+	b := binary.LittleEndian.Uint64(tmbytes.Reverse(requestID)[24:])
+	signer := (uint64(1)<<n - 1) & (b >> (64 - n - 1))
+	if signer >= uint64(len(quorums)) {
+		return nil, fmt.Errorf("%w: quorum index %d of %s is not active", ErrNoSigningQuorum, signer, params.Name)
+	}
+	return quorums[signer], nil
+}
@@ -0,0 +1,96 @@
+package crypto
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dashpay/dashd-go/btcjson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmbytes "github.com/dashpay/tenderdash/libs/bytes"
+)
+
+// The expected selections were computed with an implementation of the
+// selection of dashd in Python, independent of this package, over the
+// synthetic quorum hashes of selectionTestQuorums. They are not checked against
+// dashd, as no quorum list of a network was at hand. Real cases are still
+// needed for a type without rotation and a DIP-0024 type: the output of
+// "quorum list" at some height, a request id, and the quorumHash that
+// "quorum selectquorum" returns for it at that height.
+
+// selectionTestQuorums returns the hashes SHA256("quorum0"), SHA256("quorum1"),
+// and so on.
+func selectionTestQuorums(n int) []QuorumHash {
+	quorums := make([]QuorumHash, n)
+	for i := range quorums {
+		quorums[i] = Checksum([]byte(fmt.Sprintf("quorum%d", i)))
+	}
+	return quorums
+}
+
+func TestSelectQuorumForSigning(t *testing.T) {
+	quorums := selectionTestQuorums(64)
+	testCases := []struct {
+		llmqType  btcjson.LLMQType
+		requestID string
+		want      int
+	}{
+		// by score, among the first 2 quorums
+		{btcjson.LLMQType_TEST, "bdcf9fb3ef01209a09db19170a1950775afb5f824c5f0662b9cdae2bf3bb36d5", 0},
+		{btcjson.LLMQType_TEST, "3ff16f0155fae419d7c1402dd267a146bba71abaabb5c3d29477aa54729f3472", 1},
+		// by score, among the first 4 quorums
+		{btcjson.LLMQType_DEVNET, "bdcf9fb3ef01209a09db19170a1950775afb5f824c5f0662b9cdae2bf3bb36d5", 2},
+		{btcjson.LLMQType_DEVNET, "b06aa45eb35423f988e36c022967b4c02bb719b037717df13fa57c0f503d8a20", 3},
+		{btcjson.LLMQType_DEVNET, "2fc41ef02a3216e4311805a9a11405a41a8d7a9f179526b4f6f2866bff009a10", 0},
+		// by index, of 2 quorums
+		{btcjson.LLMQType_TEST_DIP0024, "bdcf9fb3ef01209a09db19170a1950775afb5f824c5f0662b9cdae2bf3bb36d5", 0},
+		{btcjson.LLMQType_TEST_DIP0024, "4000000000000000000000000000000000000000000000000000000000000000", 1},
+		// by index, of 32 quorums
+		{btcjson.LLMQType_60_75, "bdcf9fb3ef01209a09db19170a1950775afb5f824c5f0662b9cdae2bf3bb36d5", 15},
+		{btcjson.LLMQType_60_75, "b06aa45eb35423f988e36c022967b4c02bb719b037717df13fa57c0f503d8a20", 12},
+		{btcjson.LLMQType_60_75, "2fc41ef02a3216e4311805a9a11405a41a8d7a9f179526b4f6f2866bff009a10", 11},
+	}
+	for _, tc := range testCases {
+		selected, err := SelectQuorumForSigning(tc.llmqType, quorums, tmbytes.MustHexDecode(tc.requestID))
+		require.NoError(t, err)
+		assert.Equal(t, quorums[tc.want], selected, "%s %s", tc.llmqType.Name(), tc.requestID)
+	}
+}
+
+func TestSelectQuorumForSigningDeterministic(t *testing.T) {
+	quorums := selectionTestQuorums(4)
+	requestID := RandQuorumHash()
+	selected, err := SelectQuorumForSigning(btcjson.LLMQType_DEVNET, quorums, requestID)
+	require.NoError(t, err)
+
+	// the order of the quorums does not matter without rotation
+	reversed := []QuorumHash{quorums[3], quorums[2], quorums[1], quorums[0]}
+	again, err := SelectQuorumForSigning(btcjson.LLMQType_DEVNET, reversed, requestID)
+	require.NoError(t, err)
+	assert.Equal(t, selected, again)
+
+	// a single active quorum signs everything
+	selected, err = SelectQuorumForSigning(btcjson.LLMQType_DEVNET, quorums[:1], requestID)
+	require.NoError(t, err)
+	assert.Equal(t, quorums[0], selected)
+}
+
+func TestSelectQuorumForSigningInvalid(t *testing.T) {
+	quorums := selectionTestQuorums(2)
+	requestID := RandQuorumHash()
+
+	_, err := SelectQuorumForSigning(btcjson.LLMQType_TEST, nil, requestID)
+	assert.ErrorIs(t, err, ErrNoSigningQuorum)
+	_, err = SelectQuorumForSigning(btcjson.LLMQType(0), quorums, requestID)
+	assert.ErrorIs(t, err, ErrUnknownLLMQType)
+	_, err = SelectQuorumForSigning(btcjson.LLMQType_TEST, quorums, requestID[1:])
+	assert.ErrorIs(t, err, ErrInvalidSignHashInput)
+	_, err = SelectQuorumForSigning(btcjson.LLMQType_TEST, []QuorumHash{quorums[0], quorums[1][1:]}, requestID)
+	assert.ErrorIs(t, err, ErrInvalidSignHashInput)
+
+	// the quorum index 1 is selected, but only the quorum of index 0 is active
+	_, err = SelectQuorumForSigning(btcjson.LLMQType_TEST_DIP0024, quorums[:1],
+		tmbytes.MustHexDecode("4000000000000000000000000000000000000000000000000000000000000000"))
+	assert.ErrorIs(t, err, ErrNoSigningQuorum)
+}
@@ -0,0 +1,91 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/dashpay/dashd-go/btcjson"
+
+	tmbytes "github.com/dashpay/tenderdash/libs/bytes"
+)
+
+// ErrInvalidSignHashInput is returned for sign hash inputs of the wrong size.
+var ErrInvalidSignHashInput = errors.New("invalid sign hash input")
+
+// LLMQSignHash returns the hash that the members of a quorum sign for a
+// request, as llmq::BuildSignHash of dashd:
+//
+//	SHA256(SHA256(llmqType || quorumHash || requestID || msgHash))
+//
+// where the type is one byte. The hashes are given in the byte order dashd
+// displays them in, and reversed into the order it serializes them in. The
+// returned hash is the digest given to PrivKey.SignDigest as it is; dashd
+// displays it reversed, as the signHash of `quorum sign`.
+func LLMQSignHash(llmqType btcjson.LLMQType, quorumHash QuorumHash, requestID, msgHash []byte) ([]byte, error) {
+	if llmqType < 0 || llmqType > 0xff {
+		return nil, fmt.Errorf("%w: llmq type %d does not fit in a byte", ErrInvalidSignHashInput, llmqType)
+	}
+	for _, h := range []struct {
+		name string
+		hash []byte
+	}{{"quorum hash", quorumHash}, {"request id", requestID}, {"message hash", msgHash}} {
+		if len(h.hash) != DefaultHashSize {
+			return nil, fmt.Errorf("%w: %s has wrong size %d, expected %d",
+				ErrInvalidSignHashInput, h.name, len(h.hash), DefaultHashSize)
+		}
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(byte(llmqType))
+	buf.Write(tmbytes.Reverse(quorumHash))
+	buf.Write(tmbytes.Reverse(requestID))
+	buf.Write(tmbytes.Reverse(msgHash))
+	return Checksum(Checksum(buf.Bytes())), nil
+}
+
+// LLMQRequestID returns the id of a signing request, as dashd computes it with
+// ::SerializeHash(std::make_pair(prefix, payload)):
+//
+//	SHA256(SHA256(compactSize(len(prefix)) || prefix || payload))
+//
+// where payload is the serialization of the object the request is about. The
+// id is returned in the byte order dashd displays it in, like LLMQSignHash
+// expects it.
+func LLMQRequestID(prefix string, payload []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(compactSize(uint64(len(prefix))))
+	buf.WriteString(prefix)
+	buf.Write(payload)
+	return tmbytes.Reverse(Checksum(Checksum(buf.Bytes())))
+}
+
+// SignLLMQRequest signs the sign hash of a request with a key share of the
+// quorum, see LLMQSignHash.
+func SignLLMQRequest(
+	privKey PrivKey,
+	llmqType btcjson.LLMQType,
+	quorumHash QuorumHash,
+	requestID, msgHash []byte,
+) ([]byte, error) {
+	signHash, err := LLMQSignHash(llmqType, quorumHash, requestID, msgHash)
+	if err != nil {
+		return nil, err
+	}
+	return privKey.SignDigest(signHash)
+}
+
+// VerifyLLMQSignature reports whether sig is the signature of a request by
+// pubKey, which is either a key share or the threshold public key of the
+// quorum, see LLMQSignHash.
+func VerifyLLMQSignature(
+	pubKey PubKey,
+	llmqType btcjson.LLMQType,
+	quorumHash QuorumHash,
+	requestID, msgHash, sig []byte,
+) bool {
+	signHash, err := LLMQSignHash(llmqType, quorumHash, requestID, msgHash)
+	if err != nil {
+		return false
+	}
+	return pubKey.VerifySignatureDigest(signHash, sig)
+}
@@ -0,0 +1,117 @@
+package crypto_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/dashpay/dashd-go/btcjson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dashpay/tenderdash/crypto"
+	"github.com/dashpay/tenderdash/crypto/bls12381"
+	tmbytes "github.com/dashpay/tenderdash/libs/bytes"
+)
+
+// TestLLMQSignHash checks the result of a `quorum sign` call of dashd. The other
+// expected hashes, including those of TestLLMQRequestID, were computed with an
+// implementation of the serialization of dashd in Python, independent of this
+// package; their inputs are synthetic, not hashes of any network.
+func TestLLMQSignHash(t *testing.T) {
+	testCases := []struct {
+		llmqType   btcjson.LLMQType
+		quorumHash string
+		requestID  string
+		msgHash    string
+		signHash   string
+	}{
+		{
+			// `quorum sign 100 0000…0001 0000…0002` on regtest, as kept in the
+			// tests of github.com/dashpay/dashd-go v0.24.1. dashd shows the sign
+			// hash reversed, as 39458221939396a45a2e348caada646eabd52849990827d40e33eb1399097b3c.
+			llmqType:   btcjson.LLMQType_TEST,
+			quorumHash: "53D959F609A654CF4E5E3C083FD6C47B7EC6CB73AF4AC7329149688337B8EF9A",
+			requestID:  "0000000000000000000000000000000000000000000000000000000000000001",
+			msgHash:    "0000000000000000000000000000000000000000000000000000000000000002",
+			signHash:   "3C7B099913EB330ED42708994928D5AB6E64DAAA8C342E5AA496939321824539",
+		},
+		{
+			llmqType:   btcjson.LLMQType_TEST,
+			quorumHash: "0000000000000000000000000000000000000000000000000000000000000001",
+			requestID:  "0000000000000000000000000000000000000000000000000000000000000002",
+			msgHash:    "0000000000000000000000000000000000000000000000000000000000000003",
+			signHash:   "9B415CBF08069D55A079F6F636680B78DB493A59A089A80ABC262121080F5936",
+		},
+		{
+			// the chain lock of height 1000 of the block SHA256("block 1000")
+			// by the quorum SHA256("quorum llmq_50_60")
+			llmqType:   btcjson.LLMQType_50_60,
+			quorumHash: "E09406A47F4CAC2DF6C8BDC6A558476BB6D591A580FDB47FF17B9F774C3934A3",
+			requestID:  "038C89A716F32B9F7E7BD8F211590D7145C30CADDCEF53A0BAF4BB9A2A1B0204",
+			msgHash:    "812582E76FE035A6EA3B8A676D6C5EA736F5FAD1572ADD2C1D76E3B50AA20758",
+			signHash:   "0D83550216832A82A3F4FBED60772F33C1DE3A0141A3AD7D33DF8FD9BC129EB5",
+		},
+	}
+	for _, tc := range testCases {
+		signHash, err := crypto.LLMQSignHash(
+			tc.llmqType,
+			tmbytes.MustHexDecode(tc.quorumHash),
+			tmbytes.MustHexDecode(tc.requestID),
+			tmbytes.MustHexDecode(tc.msgHash),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, tc.signHash, tmbytes.HexBytes(signHash).String())
+	}
+}
+
+func TestLLMQSignHashInvalid(t *testing.T) {
+	hash := crypto.RandQuorumHash()
+	_, err := crypto.LLMQSignHash(btcjson.LLMQType(256), hash, hash, hash)
+	assert.ErrorIs(t, err, crypto.ErrInvalidSignHashInput)
+	_, err = crypto.LLMQSignHash(btcjson.LLMQType_TEST, hash[1:], hash, hash)
+	assert.ErrorIs(t, err, crypto.ErrInvalidSignHashInput)
+	_, err = crypto.LLMQSignHash(btcjson.LLMQType_TEST, hash, nil, hash)
+	assert.ErrorIs(t, err, crypto.ErrInvalidSignHashInput)
+	_, err = crypto.LLMQSignHash(btcjson.LLMQType_TEST, hash, hash, append(hash, 0))
+	assert.ErrorIs(t, err, crypto.ErrInvalidSignHashInput)
+}
+
+func TestLLMQRequestID(t *testing.T) {
+	// the request id of the chain lock of height 1000
+	height := make([]byte, 4)
+	binary.LittleEndian.PutUint32(height, 1000)
+	assert.Equal(t,
+		"038C89A716F32B9F7E7BD8F211590D7145C30CADDCEF53A0BAF4BB9A2A1B0204",
+		tmbytes.HexBytes(crypto.LLMQRequestID("clsig", height)).String())
+
+	payload := make([]byte, 32)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+	assert.Equal(t,
+		"B56FD3F525490F585A916D590905704FE35691C09ECA4BDF650D8F7EB6C23F99",
+		tmbytes.HexBytes(crypto.LLMQRequestID("plwdtx", payload)).String())
+}
+
+func TestSignLLMQRequest(t *testing.T) {
+	llmqType := btcjson.LLMQType_TEST
+	privKey := bls12381.GenPrivKey()
+	quorumHash := crypto.RandQuorumHash()
+	requestID := crypto.LLMQRequestID("clsig", []byte{0xe8, 0x03, 0, 0})
+	msgHash := crypto.CRandBytes(32)
+
+	sig, err := crypto.SignLLMQRequest(privKey, llmqType, quorumHash, requestID, msgHash)
+	require.NoError(t, err)
+	signHash, err := crypto.LLMQSignHash(llmqType, quorumHash, requestID, msgHash)
+	require.NoError(t, err)
+	assert.True(t, privKey.PubKey().VerifySignatureDigest(signHash, sig))
+
+	pubKey := privKey.PubKey()
+	assert.True(t, crypto.VerifyLLMQSignature(pubKey, llmqType, quorumHash, requestID, msgHash, sig))
+	assert.False(t, crypto.VerifyLLMQSignature(pubKey, btcjson.LLMQType_DEVNET, quorumHash, requestID, msgHash, sig))
+	assert.False(t, crypto.VerifyLLMQSignature(pubKey, llmqType, quorumHash, msgHash, requestID, sig))
+	assert.False(t, crypto.VerifyLLMQSignature(pubKey, llmqType, quorumHash[1:], requestID, msgHash, sig))
+
+	_, err = crypto.SignLLMQRequest(privKey, llmqType, quorumHash[1:], requestID, msgHash)
+	assert.ErrorIs(t, err, crypto.ErrInvalidSignHashInput)
+}
@@ -0,0 +1,118 @@
+package crypto
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	tmbytes "github.com/dashpay/tenderdash/libs/bytes"
+)
+
+// ErrInvalidWireMessage is returned for dashd messages that fail to
+// deserialize.
+var ErrInvalidWireMessage = errors.New("invalid wire message")
+
+// compactSize returns the variable length encoding of n of dashd.
+func compactSize(n uint64) []byte {
+	switch {
+	case n < 0xfd:
+		return []byte{byte(n)}
+	case n <= 0xffff:
+		b := []byte{0xfd, 0, 0}
+		binary.LittleEndian.PutUint16(b[1:], uint16(n))
+		return b
+	case n <= 0xffffffff:
+		b := []byte{0xfe, 0, 0, 0, 0}
+		binary.LittleEndian.PutUint32(b[1:], uint32(n))
+		return b
+	default:
+		b := []byte{0xff, 0, 0, 0, 0, 0, 0, 0, 0}
+		binary.LittleEndian.PutUint64(b[1:], n)
+		return b
+	}
+}
+
+// wireReader deserializes dashd messages. The first error is kept, and reads
+// after it return zero values.
+type wireReader struct {
+	b   []byte
+	err error
+}
+
+func (r *wireReader) read(n int, what string) []byte {
+	if r.err != nil {
+		return make([]byte, n)
+	}
+	if len(r.b) < n {
+		r.err = fmt.Errorf("%w: %s: %d bytes left, expected %d", ErrInvalidWireMessage, what, len(r.b), n)
+		return make([]byte, n)
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b
+}
+
+func (r *wireReader) uint8(what string) uint8 {
+	return r.read(1, what)[0]
+}
+
+func (r *wireReader) uint32(what string) uint32 {
+	return binary.LittleEndian.Uint32(r.read(4, what))
+}
+
+// hash reads a uint256 and returns it in the byte order dashd displays it in.
+func (r *wireReader) hash(what string) tmbytes.HexBytes {
+	return tmbytes.Reverse(r.read(DefaultHashSize, what))
+}
+
+// bytes reads n bytes into a new slice.
+func (r *wireReader) bytes(n int, what string) []byte {
+	return append([]byte(nil), r.read(n, what)...)
+}
+
+// compactSize reads a canonical compact size that counts items of itemSize
+// bytes, and checks that the message holds that many items.
+func (r *wireReader) compactSize(itemSize int, what string) int {
+	var n uint64
+	switch prefix := r.uint8(what); prefix {
+	case 0xfd:
+		n = uint64(binary.LittleEndian.Uint16(r.read(2, what)))
+		if n < 0xfd {
+			r.fail("%s: non-canonical compact size", what)
+		}
+	case 0xfe:
+		n = uint64(binary.LittleEndian.Uint32(r.read(4, what)))
+		if n <= 0xffff {
+			r.fail("%s: non-canonical compact size", what)
+		}
+	case 0xff:
+		n = binary.LittleEndian.Uint64(r.read(8, what))
+		if n <= math.MaxUint32 {
+			r.fail("%s: non-canonical compact size", what)
+		}
+	default:
+		n = uint64(prefix)
+	}
+	if r.err == nil && n > uint64(len(r.b)/itemSize) {
+		r.fail("%s: %d items do not fit in %d bytes", what, n, len(r.b))
+	}
+	if r.err != nil {
+		return 0
+	}
+	return int(n)
+}
+
+func (r *wireReader) fail(format string, args ...interface{}) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidWireMessage}, args...)...)
+	}
+}
+
+// finish returns the first error, or an error if bytes are left over.
+func (r *wireReader) finish() error {
+	if r.err == nil && len(r.b) > 0 {
+		r.fail("%d trailing bytes", len(r.b))
+	}
+	return r.err
+}
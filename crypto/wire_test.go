@@ -0,0 +1,24 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompactSize(t *testing.T) {
+	testCases := []struct {
+		n    uint64
+		want []byte
+	}{
+		{0, []byte{0}},
+		{0xfc, []byte{0xfc}},
+		{0xfd, []byte{0xfd, 0xfd, 0}},
+		{0xffff, []byte{0xfd, 0xff, 0xff}},
+		{0x10000, []byte{0xfe, 0, 0, 1, 0}},
+		{0x100000000, []byte{0xff, 0, 0, 0, 0, 1, 0, 0, 0}},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, compactSize(tc.n), tc.n)
+	}
+}
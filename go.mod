@@ -6,8 +6,12 @@ require (
 	github.com/dashpay/bls-signatures/go-bindings v0.0.0-20230207105415-06df92693ac8
 	github.com/dashpay/dashd-go v0.24.1
 	github.com/dashpay/dashd-go/btcec/v2 v2.1.0 // indirect
+	github.com/kilic/bls12-381 v0.1.0
 	github.com/stretchr/testify v1.8.4
-	golang.org/x/crypto v0.14.0 // indirect
+	golang.org/x/crypto v0.14.0
+	golang.org/x/sys v0.13.0
+	golang.org/x/text v0.13.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -22,5 +26,4 @@ require (
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )